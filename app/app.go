@@ -15,6 +15,7 @@ import (
 type App struct {
 	Config         *mblconfig.Config
 	Client         *client.Client
+	Network        custom.Network // profile of the chain, which encodes its addresses
 	DB             *db.Database
 	RawDB          *db.RawDatabase
 	ChainNumMap    map[int]string
@@ -24,6 +25,24 @@ type App struct {
 	CatchingUp     bool // exporter가 최신 블록을 트레킹 중이면 false
 }
 
+// nodeFallbacks are the base names of config files whose client configuration is used
+// when the node of the main config file fails.
+var nodeFallbacks []string
+
+// SetNodeFallbacks sets the config files of other nodes of the same chain. It must be called before NewApp.
+func SetNodeFallbacks(names []string) {
+	nodeFallbacks = names
+}
+
+// archiveNodes are the base names of config files whose client configuration points to archive nodes,
+// which serve the heights pruned nodes no longer keep.
+var archiveNodes []string
+
+// SetArchiveNodes sets the config files of archive nodes of the same chain. It must be called before NewApp.
+func SetArchiveNodes(names []string) {
+	archiveNodes = names
+}
+
 func init() {
 	if !custom.IsSetAppConfig() {
 		panic(fmt.Errorf("appconfig was not set"))
@@ -43,11 +62,35 @@ func NewApp(fileBaseName string) *App {
 	app.CatchingUp = false
 	app.Config = mblconfig.ParseConfig(fileBaseName)
 
-	app.Client = client.NewClient(&app.Config.Client)
+	fallbacks := make([]*mblconfig.ClientConfig, 0, len(nodeFallbacks))
+	for _, name := range nodeFallbacks {
+		fallbacks = append(fallbacks, &mblconfig.ParseConfig(name).Client)
+	}
+	app.Client = client.NewClient(&app.Config.Client, fallbacks...)
+
+	archives := make([]*mblconfig.ClientConfig, 0, len(archiveNodes))
+	for _, name := range archiveNodes {
+		archives = append(archives, &mblconfig.ParseConfig(name).Client)
+	}
+	app.Client.AddArchiveNodes(archives...)
+
+	custom.SetAppConfig()
+	app.Network = custom.CurrentNetwork
+	app.Client.Network = app.Network
+	chainID, err := app.Client.GetNetworkChainID()
+	if err != nil {
+		panic(err)
+	}
+	if err := app.Network.ValidateChainID(chainID); err != nil {
+		panic(err)
+	}
+	if err := validateChainLineage(chainID); err != nil {
+		panic(err)
+	}
 
 	if fileBaseName == "chain-exporter" {
 		app.DB = db.Connect(&app.Config.DB)
-		err := app.DB.Ping()
+		err = app.DB.Ping()
 		if err != nil {
 			panic(err)
 		}
@@ -60,6 +103,21 @@ func NewApp(fileBaseName string) *App {
 		mdschema.SetCommonSchema(app.Config.DB.CommonSchema)
 		mdschema.SetChainSchema(app.Config.DB.ChainSchema)
 
+		err = app.DB.CreateLocalTables()
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	// mintscan reads the tables the exporter writes for its search, gas and fee endpoints, and never writes to them
+	if fileBaseName == "mintscan" {
+		app.DB = db.Connect(&app.Config.DB)
+		err = app.DB.Ping()
+		if err != nil {
+			panic(err)
+		}
+		mdschema.SetCommonSchema(app.Config.DB.CommonSchema)
+		mdschema.SetChainSchema(app.Config.DB.ChainSchema)
 	}
 
 	// app.DB.AddQueryHook(dbLogger{})    // debugging 용
@@ -68,12 +126,54 @@ func NewApp(fileBaseName string) *App {
 	return app
 }
 
+// NewChainApp returns the App of another chain of the network profile indexed by the same process.
+// The chain-id, the node and the chain schemas are read from the config file of the chain, and the databases of base are used
+// through pools of the chain, since the chain schemas are bound to the connections of a pool.
+// Validators, proposals and other tables of a chain schema are not keyed by chain, so the chain schemas must differ from those of base.
+// Addresses of the chain are encoded with the prefix of its profile, which may differ from the prefix of the sdk config.
+func NewChainApp(base *App, fileBaseName string, network custom.Network) *App {
+	app := new(App)
+	app.CatchingUp = false
+	app.Config = mblconfig.ParseConfig(fileBaseName)
+	schema, rawSchema := app.Config.DB.ChainSchema, app.Config.RAWDB.ChainSchema
+	if schema == base.Config.DB.ChainSchema || rawSchema == base.Config.RAWDB.ChainSchema {
+		panic(fmt.Errorf("chain schemas of %s must differ from %s and %s", fileBaseName, base.Config.DB.ChainSchema, base.Config.RAWDB.ChainSchema))
+	}
+	app.Config.DB = base.Config.DB
+	app.Config.DB.ChainSchema = schema
+	app.Config.RAWDB = base.Config.RAWDB
+	app.Config.RAWDB.ChainSchema = rawSchema
+	app.Network = network
+	app.Client = client.NewClient(&app.Config.Client)
+	app.Client.Network = network
+
+	chainID, err := app.Client.GetNetworkChainID()
+	if err != nil {
+		panic(err)
+	}
+	if err := app.Network.ValidateChainID(chainID); err != nil {
+		panic(err)
+	}
+
+	app.DB = db.ConnectChain(&app.Config.DB)
+	if err := app.DB.Ping(); err != nil {
+		panic(err)
+	}
+	app.RawDB = db.RawDBConnectChain(&app.Config.RAWDB)
+	if err := app.RawDB.Ping(); err != nil {
+		panic(err)
+	}
+	if err := app.DB.CreateLocalTables(); err != nil {
+		panic(err)
+	}
+
+	return app
+}
+
 // SetChainID ChainID를 할당하고, DB에서 InsertSelect()하여 맵을 구성
 func (a *App) SetChainID() {
-	a.ChainIDMap = make(map[string]int)
-	a.ChainNumMap = make(map[int]string)
 	if a.Config.Chain.ChainID == "" {
-		chainID, err := a.Client.RPC.GetNetworkChainID()
+		chainID, err := a.Client.GetNetworkChainID()
 		if err != nil {
 			panic(err)
 		}
@@ -92,18 +192,46 @@ func (a *App) SetChainID() {
 		}
 	}
 
+	if err := a.LoadChainInfo(); err != nil {
+		panic(err)
+	}
+}
+
+// LoadChainInfo builds ChainIDMap and ChainNumMap from the chain_info table without writing to it.
+// It fails when the chain-id of the config is not stored, as for mintscan reading a chain the exporter has not stored yet.
+func (a *App) LoadChainInfo() error {
 	chainInfo, err := a.DB.GetChainInfo()
 	if err != nil {
-		panic(err)
+		return fmt.Errorf("failed to get chain info: %s", err)
 	}
 
+	a.ChainIDMap = make(map[string]int)
+	a.ChainNumMap = make(map[int]string)
 	for _, c := range chainInfo {
 		a.ChainNumMap[int(c.ID)] = c.ChainID
 		a.ChainIDMap[c.ChainID] = int(c.ID)
 	}
+	if _, ok := a.ChainIDMap[a.Config.Chain.ChainID]; !ok {
+		return fmt.Errorf("chain-id %s is not stored in chain_info", a.Config.Chain.ChainID)
+	}
 
 	fmt.Println("ChainIDMap :", a.ChainIDMap)
 	fmt.Println("ChainNumMap :", a.ChainNumMap)
+	return nil
+}
+
+// Close closes the database connection pools.
+func (a *App) Close() {
+	if a.DB != nil {
+		if err := a.DB.Close(); err != nil {
+			zap.S().Errorf("failed to close database: %s", err)
+		}
+	}
+	if a.RawDB != nil {
+		if err := a.RawDB.Close(); err != nil {
+			zap.S().Errorf("failed to close raw database: %s", err)
+		}
+	}
 }
 
 func (a *App) SetMessageInfo() {
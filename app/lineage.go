@@ -0,0 +1,124 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ChainSegment is the range of heights produced under a chain-id. EndHeight is 0 while the chain is live.
+type ChainSegment struct {
+	ChainID     string `json:"chain_id"`
+	StartHeight int64  `json:"start_height"`
+	EndHeight   int64  `json:"end_height,omitempty"`
+}
+
+// chainLineage is the chain-ids a network was launched with, ordered by height.
+var chainLineage []ChainSegment
+
+// SetChainLineage sets the chain-ids of the network across hard forks. It must be called before NewApp.
+func SetChainLineage(segments []ChainSegment) {
+	chainLineage = segments
+}
+
+// ParseChainLineage parses segments in the form of chain-id:start-end separated by commas,
+// e.g. coreum-mainnet-1:1-1000,coreum-mainnet-2:1001-. The end height of the last segment may be omitted.
+// Segments must be ordered and continue each other without a gap.
+func ParseChainLineage(s string) ([]ChainSegment, error) {
+	var segments []ChainSegment
+	for _, part := range strings.Split(s, ",") {
+		chainID, heights, ok := strings.Cut(strings.TrimSpace(part), ":")
+		if !ok || chainID == "" {
+			return nil, fmt.Errorf("invalid chain segment : %s", part)
+		}
+		start, end, _ := strings.Cut(heights, "-")
+
+		seg := ChainSegment{ChainID: chainID}
+		var err error
+		if seg.StartHeight, err = strconv.ParseInt(start, 10, 64); err != nil || seg.StartHeight < 1 {
+			return nil, fmt.Errorf("invalid start height of chain segment %s : %s", part, start)
+		}
+		if end != "" {
+			if seg.EndHeight, err = strconv.ParseInt(end, 10, 64); err != nil || seg.EndHeight < seg.StartHeight {
+				return nil, fmt.Errorf("invalid end height of chain segment %s : %s", part, end)
+			}
+		}
+
+		if n := len(segments); n > 0 {
+			prev := segments[n-1]
+			if prev.EndHeight == 0 || prev.EndHeight+1 != seg.StartHeight {
+				return nil, fmt.Errorf("chain segment %s does not continue %s", seg.ChainID, prev.ChainID)
+			}
+		}
+		segments = append(segments, seg)
+	}
+
+	return segments, nil
+}
+
+// ChainLineage returns the chain-ids of the network across hard forks. It is empty when no lineage is configured.
+func (a *App) ChainLineage() []ChainSegment {
+	return chainLineage
+}
+
+// ChainSegment returns the segment of the chain-id in the lineage.
+func (a *App) ChainSegment(chainID string) (ChainSegment, bool) {
+	for _, seg := range chainLineage {
+		if seg.ChainID == chainID {
+			return seg, true
+		}
+	}
+	return ChainSegment{}, false
+}
+
+// ChainIDAt returns the chain-id which produced the height, falling back to the chain-id of the stored row
+// when the height is not covered by the lineage. The lineage only applies to rows of a chain-id of the lineage,
+// so rows of other chains indexed by the same database keep their chain-id.
+func (a *App) ChainIDAt(height int64, chainInfoID int) string {
+	chainID := a.ChainNumMap[chainInfoID]
+	if _, ok := a.ChainSegment(chainID); !ok {
+		return chainID
+	}
+	for _, seg := range chainLineage {
+		if height >= seg.StartHeight && (seg.EndHeight == 0 || height <= seg.EndHeight) {
+			return seg.ChainID
+		}
+	}
+	return chainID
+}
+
+// ChainIDs returns the chain-id of the config and the other chain-ids of the lineage.
+func (a *App) ChainIDs() []string {
+	chainIDs := []string{a.Config.Chain.ChainID}
+	for _, seg := range chainLineage {
+		if seg.ChainID != a.Config.Chain.ChainID {
+			chainIDs = append(chainIDs, seg.ChainID)
+		}
+	}
+	return chainIDs
+}
+
+// ChainInfoIDs returns the ids of the chain info of the chain-id of the config and of the other chain-ids of the lineage
+// stored in database, so that queries cover the history of the network across hard forks.
+func (a *App) ChainInfoIDs() []int {
+	ids := []int{a.ChainIDMap[a.Config.Chain.ChainID]}
+	for _, seg := range chainLineage {
+		if id, ok := a.ChainIDMap[seg.ChainID]; ok && seg.ChainID != a.Config.Chain.ChainID {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// validateChainLineage returns an error when a lineage is configured and the chain-id is not part of it.
+func validateChainLineage(chainID string) error {
+	if len(chainLineage) == 0 {
+		return nil
+	}
+	for _, seg := range chainLineage {
+		if seg.ChainID == chainID {
+			return nil
+		}
+	}
+	return fmt.Errorf("chain-id of the node %s is not in the chain lineage", chainID)
+}
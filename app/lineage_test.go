@@ -0,0 +1,65 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	mblconfig "github.com/cosmostation/mintscan-backend-library/config"
+)
+
+func TestParseChainLineage(t *testing.T) {
+	segments, err := ParseChainLineage("coreum-mainnet-1:1-1000, coreum-mainnet-2:1001-")
+	require.NoError(t, err)
+	require.Equal(t, []ChainSegment{
+		{ChainID: "coreum-mainnet-1", StartHeight: 1, EndHeight: 1000},
+		{ChainID: "coreum-mainnet-2", StartHeight: 1001},
+	}, segments)
+
+	for _, s := range []string{
+		"coreum-mainnet-1",
+		"coreum-mainnet-1:0-10",
+		"coreum-mainnet-1:10-1",
+		"coreum-mainnet-1:1-1000,coreum-mainnet-2:1002-",
+		"coreum-mainnet-1:1-,coreum-mainnet-2:1001-",
+	} {
+		_, err := ParseChainLineage(s)
+		require.Error(t, err, s)
+	}
+}
+
+func TestChainIDAt(t *testing.T) {
+	defer SetChainLineage(nil)
+
+	a := &App{ChainNumMap: map[int]string{1: "coreum-mainnet-2", 2: "coreum-testnet-1"}}
+	require.Equal(t, "coreum-mainnet-2", a.ChainIDAt(10, 1))
+
+	SetChainLineage([]ChainSegment{
+		{ChainID: "coreum-mainnet-1", StartHeight: 1, EndHeight: 1000},
+		{ChainID: "coreum-mainnet-2", StartHeight: 1001},
+	})
+	require.Equal(t, "coreum-mainnet-1", a.ChainIDAt(1000, 1))
+	require.Equal(t, "coreum-mainnet-2", a.ChainIDAt(1001, 1))
+	require.Equal(t, "coreum-testnet-1", a.ChainIDAt(500, 2))
+	require.NoError(t, validateChainLineage("coreum-mainnet-2"))
+	require.Error(t, validateChainLineage("coreum-testnet-1"))
+}
+
+func TestChainInfoIDs(t *testing.T) {
+	defer SetChainLineage(nil)
+
+	cfg := new(mblconfig.Config)
+	cfg.Chain.ChainID = "coreum-mainnet-2"
+	a := &App{Config: cfg, ChainIDMap: map[string]int{"coreum-mainnet-1": 1, "coreum-mainnet-2": 2, "coreum-testnet-1": 3}}
+	require.Equal(t, []int{2}, a.ChainInfoIDs())
+	require.Equal(t, []string{"coreum-mainnet-2"}, a.ChainIDs())
+
+	SetChainLineage([]ChainSegment{
+		{ChainID: "coreum-mainnet-0", StartHeight: 1, EndHeight: 10},
+		{ChainID: "coreum-mainnet-1", StartHeight: 11, EndHeight: 1000},
+		{ChainID: "coreum-mainnet-2", StartHeight: 1001},
+	})
+	// chain-ids of the lineage which are not stored are skipped
+	require.Equal(t, []int{2, 1}, a.ChainInfoIDs())
+	require.Equal(t, []string{"coreum-mainnet-2", "coreum-mainnet-0", "coreum-mainnet-1"}, a.ChainIDs())
+}
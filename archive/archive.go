@@ -0,0 +1,125 @@
+// Package archive stores raw chain data in height-ranged, zstd compressed NDJSON files on a local path.
+// Every completed file is listed with its height range and SHA-256 checksum in a manifest,
+// so that the data can be replayed after the raw database has been pruned.
+package archive
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// ManifestVersion is the version of the manifest and file layout written by Writer.
+	ManifestVersion = 1
+
+	// FormatNDJSONZstd is a file of an Entry per line compressed with zstd.
+	FormatNDJSONZstd = "ndjson+zstd"
+
+	manifestName = "manifest.json"
+)
+
+// Entry is the raw data of a height. Block is the JSON of the block and Txs are the JSON of its transactions.
+type Entry struct {
+	ChainID   string          `json:"chain_id"`
+	Height    int64           `json:"height"`
+	BlockHash string          `json:"block_hash"`
+	Block     json.RawMessage `json:"block"`
+	Txs       []Tx            `json:"txs,omitempty"`
+}
+
+// Tx is the raw data of a transaction.
+type Tx struct {
+	Hash  string          `json:"hash"`
+	Chunk json.RawMessage `json:"chunk"`
+}
+
+// File is a completed archive file holding the heights from From to To.
+type File struct {
+	Name   string `json:"name"`
+	From   int64  `json:"from"`
+	To     int64  `json:"to"`
+	NumTxs int64  `json:"num_txs"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Manifest lists the completed archive files of a chain in height order.
+type Manifest struct {
+	Version int    `json:"version"`
+	ChainID string `json:"chain_id"`
+	Format  string `json:"format"`
+	Files   []File `json:"files"`
+}
+
+// LastHeight returns the last archived height, 0 when nothing is archived.
+func (m *Manifest) LastHeight() int64 {
+	if len(m.Files) == 0 {
+		return 0
+	}
+	return m.Files[len(m.Files)-1].To
+}
+
+// chainDir returns the directory of the archive of the chain.
+func chainDir(path, chainID string) string {
+	return filepath.Join(path, chainID)
+}
+
+// ReadManifest reads the manifest of the archive of the chain. An empty manifest is returned when nothing is archived.
+func ReadManifest(path, chainID string) (*Manifest, error) {
+	m := &Manifest{Version: ManifestVersion, ChainID: chainID, Format: FormatNDJSONZstd}
+
+	b, err := os.ReadFile(filepath.Join(chainDir(path, chainID), manifestName))
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %s", err)
+	}
+
+	if err := json.Unmarshal(b, m); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal manifest: %s", err)
+	}
+	if m.Version != ManifestVersion || m.Format != FormatNDJSONZstd {
+		return nil, fmt.Errorf("unsupported manifest version %d and format %s", m.Version, m.Format)
+	}
+	if m.ChainID != chainID {
+		return nil, fmt.Errorf("manifest belongs to %s, not %s", m.ChainID, chainID)
+	}
+	return m, nil
+}
+
+// writeManifest replaces the manifest atomically.
+func writeManifest(dir string, m *Manifest) error {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %s", err)
+	}
+
+	tmp := filepath.Join(dir, manifestName+".tmp")
+	if err := writeFileSync(tmp, b); err != nil {
+		return fmt.Errorf("failed to write manifest: %s", err)
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, manifestName)); err != nil {
+		return fmt.Errorf("failed to replace manifest: %s", err)
+	}
+	return nil
+}
+
+// writeFileSync writes b to the file and flushes it to disk.
+func writeFileSync(name string, b []byte) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
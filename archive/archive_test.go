@@ -0,0 +1,89 @@
+package archive
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func entry(height int64) Entry {
+	return Entry{
+		ChainID:   "coreum-testnet-1",
+		Height:    height,
+		BlockHash: fmt.Sprintf("HASH%d", height),
+		Block:     json.RawMessage(fmt.Sprintf(`{"height":"%d"}`, height)),
+		Txs:       []Tx{{Hash: fmt.Sprintf("TX%d", height), Chunk: json.RawMessage(`{"code":0}`)}},
+	}
+}
+
+func TestWriteAndReplay(t *testing.T) {
+	path := t.TempDir()
+
+	w, err := NewWriter(path, "coreum-testnet-1", 10)
+	require.NoError(t, err)
+	require.Zero(t, w.NextHeight())
+	for h := int64(5); h <= 25; h++ {
+		require.NoError(t, w.Write(entry(h)))
+	}
+	require.Error(t, w.Write(entry(30)))
+	require.NoError(t, w.Close())
+
+	// heights of the incomplete file are written again by the next writer
+	w, err = NewWriter(path, "coreum-testnet-1", 10)
+	require.NoError(t, err)
+	require.Equal(t, int64(21), w.NextHeight())
+	require.NoError(t, w.Close())
+
+	m, err := ReadManifest(path, "coreum-testnet-1")
+	require.NoError(t, err)
+	require.Len(t, m.Files, 2)
+	require.Equal(t, File{Name: m.Files[0].Name, From: 5, To: 10, NumTxs: 6, Size: m.Files[0].Size, SHA256: m.Files[0].SHA256}, m.Files[0])
+	require.Equal(t, int64(11), m.Files[1].From)
+	require.Equal(t, int64(20), m.LastHeight())
+
+	r, err := OpenReader(path, "coreum-testnet-1")
+	require.NoError(t, err)
+
+	var heights []int64
+	var batches int
+	err = r.Replay(8, 4, func(entries []Entry) error {
+		batches++
+		for _, e := range entries {
+			require.Equal(t, entry(e.Height), e)
+			heights = append(heights, e.Height)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, heights, 13)
+	require.Equal(t, int64(8), heights[0])
+	require.Equal(t, int64(20), heights[12])
+	require.Equal(t, 4, batches)
+}
+
+func TestReplayChecksumMismatch(t *testing.T) {
+	path := t.TempDir()
+
+	w, err := NewWriter(path, "coreum-testnet-1", 5)
+	require.NoError(t, err)
+	for h := int64(1); h <= 5; h++ {
+		require.NoError(t, w.Write(entry(h)))
+	}
+
+	m, err := ReadManifest(path, "coreum-testnet-1")
+	require.NoError(t, err)
+	name := filepath.Join(path, "coreum-testnet-1", m.Files[0].Name)
+	b, err := os.ReadFile(name)
+	require.NoError(t, err)
+	b[len(b)-1] ^= 0xff
+	require.NoError(t, os.WriteFile(name, b, 0o644))
+
+	r, err := OpenReader(path, "coreum-testnet-1")
+	require.NoError(t, err)
+	err = r.Replay(1, 10, func(entries []Entry) error { return nil })
+	require.ErrorContains(t, err, "checksum mismatch")
+}
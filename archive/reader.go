@@ -0,0 +1,120 @@
+package archive
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// maxEntrySize bounds the size of a line in an archive file.
+const maxEntrySize = 256 << 20
+
+// Reader replays the archive of a chain.
+type Reader struct {
+	dir      string
+	manifest *Manifest
+}
+
+// OpenReader returns a reader of the archive of the chain under path.
+func OpenReader(path, chainID string) (*Reader, error) {
+	m, err := ReadManifest(path, chainID)
+	if err != nil {
+		return nil, err
+	}
+	return &Reader{dir: chainDir(path, chainID), manifest: m}, nil
+}
+
+// Manifest returns the manifest of the archive.
+func (r *Reader) Manifest() *Manifest {
+	return r.manifest
+}
+
+// Replay calls fn with the entries of every height from the height on, at most batchSize entries at a time.
+// The checksum of each file is verified before its entries are read.
+func (r *Reader) Replay(from int64, batchSize int, fn func(entries []Entry) error) error {
+	if batchSize <= 0 {
+		return fmt.Errorf("invalid batch size %d", batchSize)
+	}
+
+	for _, file := range r.manifest.Files {
+		if file.To < from {
+			continue
+		}
+		if err := r.verify(file); err != nil {
+			return err
+		}
+		if err := r.replayFile(file, from, batchSize, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verify compares the checksum of the file with the manifest.
+func (r *Reader) verify(file File) error {
+	f, err := os.Open(filepath.Join(r.dir, file.Name))
+	if err != nil {
+		return fmt.Errorf("failed to open archive file: %s", err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return fmt.Errorf("failed to read archive file %s: %s", file.Name, err)
+	}
+	if sum := hex.EncodeToString(h.Sum(nil)); sum != file.SHA256 {
+		return fmt.Errorf("checksum mismatch of archive file %s: %s, manifest %s", file.Name, sum, file.SHA256)
+	}
+	return nil
+}
+
+func (r *Reader) replayFile(file File, from int64, batchSize int, fn func(entries []Entry) error) error {
+	f, err := os.Open(filepath.Join(r.dir, file.Name))
+	if err != nil {
+		return fmt.Errorf("failed to open archive file: %s", err)
+	}
+	defer f.Close()
+
+	zr, err := zstd.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("failed to create zstd reader: %s", err)
+	}
+	defer zr.Close()
+
+	s := bufio.NewScanner(zr)
+	s.Buffer(make([]byte, 0, 1<<20), maxEntrySize)
+
+	batch := make([]Entry, 0, batchSize)
+	for s.Scan() {
+		var e Entry
+		if err := json.Unmarshal(s.Bytes(), &e); err != nil {
+			return fmt.Errorf("failed to unmarshal entry of archive file %s: %s", file.Name, err)
+		}
+		if e.Height < from {
+			continue
+		}
+
+		batch = append(batch, e)
+		if len(batch) == batchSize {
+			if err := fn(batch); err != nil {
+				return err
+			}
+			batch = make([]Entry, 0, batchSize)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return fmt.Errorf("failed to read archive file %s: %s", file.Name, err)
+	}
+
+	if len(batch) > 0 {
+		return fn(batch)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package archive
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Writer appends entries of consecutive heights to the archive of a chain.
+// A file is added to the manifest when its last height is written. Heights of an incomplete file are
+// discarded by Close and written again by the next Writer, which continues after the last height of the manifest.
+type Writer struct {
+	dir       string
+	rangeSize int64
+	manifest  *Manifest
+
+	// file being written
+	cur  *File
+	f    *os.File
+	h    hash.Hash
+	n    *countWriter
+	zw   *zstd.Encoder
+	bw   *bufio.Writer
+	next int64
+}
+
+// NewWriter returns a writer of the archive of the chain under path. Each file holds rangeSize heights,
+// aligned to multiples of rangeSize, except the first file which starts at the first written height.
+func NewWriter(path, chainID string, rangeSize int64) (*Writer, error) {
+	if rangeSize <= 0 {
+		return nil, fmt.Errorf("invalid archive range size %d", rangeSize)
+	}
+
+	dir := chainDir(path, chainID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create archive directory: %s", err)
+	}
+
+	m, err := ReadManifest(path, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Writer{dir: dir, rangeSize: rangeSize, manifest: m}
+	if last := m.LastHeight(); last > 0 {
+		w.next = last + 1
+	}
+	return w, nil
+}
+
+// NextHeight returns the height the next entry must have, 0 when the archive is empty and any height may begin it.
+func (w *Writer) NextHeight() int64 {
+	return w.next
+}
+
+// Write appends the entry. Entries must be written in consecutive heights.
+func (w *Writer) Write(e Entry) error {
+	if w.next != 0 && e.Height != w.next {
+		return fmt.Errorf("archive expects height %d, got %d", w.next, e.Height)
+	}
+
+	if w.cur == nil {
+		if err := w.open(e.Height); err != nil {
+			return err
+		}
+	}
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to marshal archive entry: %s", err)
+	}
+	if _, err := w.bw.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write archive entry: %s", err)
+	}
+	w.cur.NumTxs += int64(len(e.Txs))
+	w.next = e.Height + 1
+
+	if e.Height == w.cur.To {
+		return w.finish()
+	}
+	return nil
+}
+
+// open starts a file at the height.
+func (w *Writer) open(from int64) error {
+	to := ((from-1)/w.rangeSize + 1) * w.rangeSize
+	cur := &File{Name: fmt.Sprintf("%012d-%012d.ndjson.zst", from, to), From: from, To: to}
+
+	f, err := os.Create(filepath.Join(w.dir, cur.Name+".tmp"))
+	if err != nil {
+		return fmt.Errorf("failed to create archive file: %s", err)
+	}
+
+	w.h = sha256.New()
+	w.n = &countWriter{w: io.MultiWriter(f, w.h)}
+	zw, err := zstd.NewWriter(w.n)
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to create zstd writer: %s", err)
+	}
+
+	w.cur, w.f, w.zw, w.bw = cur, f, zw, bufio.NewWriter(zw)
+	return nil
+}
+
+// finish completes the current file and adds it to the manifest.
+func (w *Writer) finish() error {
+	if err := w.bw.Flush(); err != nil {
+		return fmt.Errorf("failed to flush archive file: %s", err)
+	}
+	if err := w.zw.Close(); err != nil {
+		return fmt.Errorf("failed to close zstd writer: %s", err)
+	}
+	if err := w.f.Sync(); err != nil {
+		return fmt.Errorf("failed to sync archive file: %s", err)
+	}
+	if err := w.f.Close(); err != nil {
+		return fmt.Errorf("failed to close archive file: %s", err)
+	}
+
+	name := filepath.Join(w.dir, w.cur.Name)
+	if err := os.Rename(name+".tmp", name); err != nil {
+		return fmt.Errorf("failed to rename archive file: %s", err)
+	}
+
+	w.cur.Size = w.n.n
+	w.cur.SHA256 = hex.EncodeToString(w.h.Sum(nil))
+	w.manifest.Files = append(w.manifest.Files, *w.cur)
+	w.cur, w.f = nil, nil
+
+	return writeManifest(w.dir, w.manifest)
+}
+
+// Close discards the incomplete file. Its heights are written again by the next Writer.
+func (w *Writer) Close() error {
+	if w.cur == nil {
+		return nil
+	}
+	w.zw.Close()
+	w.f.Close()
+	err := os.Remove(filepath.Join(w.dir, w.cur.Name+".tmp"))
+	w.cur, w.f = nil, nil
+	w.next = 0
+	if last := w.manifest.LastHeight(); last > 0 {
+		w.next = last + 1
+	}
+	return err
+}
+
+// countWriter counts the bytes written to w.
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -4,18 +4,26 @@ import (
 	"context"
 
 	sdktypes "github.com/cosmos/cosmos-sdk/types"
-	mbltypes "github.com/cosmostation/mintscan-backend-library/types"
 )
 
 var (
 	pageLimit = uint64(100)
 )
 
+// GetBondDenom returns the bond denom of the staking module.
+func (c *Client) GetBondDenom(ctx context.Context) (denom string, err error) {
+	err = c.do("GetBondDenom", func(e *endpoint) error {
+		denom, err = e.GRPC.GetBondDenom(ctx)
+		return err
+	})
+	return denom, err
+}
+
 // GetBaseAccountTotalAsset returns coins against bonded-denom from a delegator.
 // returns spendable, delegated, undelegated, rewards, commission
 func (c *Client) GetBaseAccountTotalAsset(address string) (sdktypes.Coin, sdktypes.Coin, sdktypes.Coin, sdktypes.Coin, sdktypes.Coin, error) {
 	ctx := context.Background()
-	denom, err := c.GRPC.GetBondDenom(ctx)
+	denom, err := c.GetBondDenom(ctx)
 	if err != nil {
 		return sdktypes.Coin{}, sdktypes.Coin{}, sdktypes.Coin{}, sdktypes.Coin{}, sdktypes.Coin{}, err
 	}
@@ -26,60 +34,87 @@ func (c *Client) GetBaseAccountTotalAsset(address string) (sdktypes.Coin, sdktyp
 	rewards := sdktypes.NewCoin(denom, sdktypes.NewInt(0))
 	commission := sdktypes.NewCoin(denom, sdktypes.NewInt(0))
 
-	resAvailable, err := c.GRPC.GetBalance(ctx, denom, address)
+	err = c.do("GetBalance", func(e *endpoint) error {
+		resAvailable, err := e.GRPC.GetBalance(ctx, denom, address)
+		if err != nil {
+			return err
+		}
+		if resAvailable != nil {
+			available = available.Add(*resAvailable)
+		}
+		return nil
+	})
 	if err != nil {
 		return sdktypes.Coin{}, sdktypes.Coin{}, sdktypes.Coin{}, sdktypes.Coin{}, sdktypes.Coin{}, err
 	}
-	if resAvailable != nil {
-		available = available.Add(*resAvailable)
-	}
 
 	// Get total delegated coins.
-	delegatorDelegationsResp, err := c.GRPC.GetDelegatorDelegations(ctx, address, pageLimit)
+	err = c.do("GetDelegatorDelegations", func(e *endpoint) error {
+		delegatorDelegationsResp, err := e.GRPC.GetDelegatorDelegations(ctx, address, pageLimit)
+		if err != nil {
+			return err
+		}
+		for _, delegation := range delegatorDelegationsResp.DelegationResponses {
+			delegated = delegated.Add(delegation.Balance)
+		}
+		return nil
+	})
 	if err != nil {
 		return sdktypes.Coin{}, sdktypes.Coin{}, sdktypes.Coin{}, sdktypes.Coin{}, sdktypes.Coin{}, err
 	}
 
-	for _, delegation := range delegatorDelegationsResp.DelegationResponses {
-		delegated = delegated.Add(delegation.Balance)
-	}
-
 	// Get total undelegated coins.
-	unbondingDelegationsResp, err := c.GRPC.GetDelegatorUnbondingDelegations(ctx, address, pageLimit)
+	err = c.do("GetDelegatorUnbondingDelegations", func(e *endpoint) error {
+		unbondingDelegationsResp, err := e.GRPC.GetDelegatorUnbondingDelegations(ctx, address, pageLimit)
+		if err != nil {
+			return err
+		}
+		for _, undelegation := range unbondingDelegationsResp.UnbondingResponses {
+			for _, e := range undelegation.Entries {
+				undelegated = undelegated.Add(sdktypes.NewCoin(denom, e.Balance))
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		return sdktypes.Coin{}, sdktypes.Coin{}, sdktypes.Coin{}, sdktypes.Coin{}, sdktypes.Coin{}, err
 	}
 
-	for _, undelegation := range unbondingDelegationsResp.UnbondingResponses {
-		for _, e := range undelegation.Entries {
-			undelegated = undelegated.Add(sdktypes.NewCoin(denom, e.Balance))
-		}
-	}
-
 	// total Rewards
-	totalRewardsResp, err := c.GRPC.GetDelegationTotalRewards(ctx, address)
+	err = c.do("GetDelegationTotalRewards", func(e *endpoint) error {
+		totalRewardsResp, err := e.GRPC.GetDelegationTotalRewards(ctx, address)
+		if err != nil {
+			return err
+		}
+		if totalRewardsResp != nil {
+			rewards = rewards.Add(sdktypes.NewCoin(denom, totalRewardsResp.Total.AmountOf(denom).TruncateInt()))
+		}
+		return nil
+	})
 	if err != nil {
 		return sdktypes.Coin{}, sdktypes.Coin{}, sdktypes.Coin{}, sdktypes.Coin{}, sdktypes.Coin{}, err
 	}
-	if totalRewardsResp != nil {
-		rewards = rewards.Add(sdktypes.NewCoin(denom, totalRewardsResp.Total.AmountOf(denom).TruncateInt()))
-	}
 
-	valAddr, err := mbltypes.ConvertValAddrFromAccAddr(address)
+	valAddr, err := c.Network.ConvertValAddrFromAccAddr(address)
 	if err != nil {
 		return sdktypes.Coin{}, sdktypes.Coin{}, sdktypes.Coin{}, sdktypes.Coin{}, sdktypes.Coin{}, err
 	}
 
 	// Get total commission
-	commissions, err := c.GRPC.GetValidatorCommission(ctx, valAddr)
+	err = c.do("GetValidatorCommission", func(e *endpoint) error {
+		commissions, err := e.GRPC.GetValidatorCommission(ctx, valAddr)
+		if err != nil {
+			return err
+		}
+		for _, c := range commissions.Commission {
+			comm, _ := c.TruncateDecimal()
+			commission = commission.Add(comm)
+		}
+		return nil
+	})
 	if err != nil {
 		return sdktypes.Coin{}, sdktypes.Coin{}, sdktypes.Coin{}, sdktypes.Coin{}, sdktypes.Coin{}, err
 	}
 
-	for _, c := range commissions.Commission {
-		comm, _ := c.TruncateDecimal()
-		commission = commission.Add(comm)
-	}
-
 	return available, delegated, undelegated, rewards, commission, nil
 }
@@ -1,6 +1,9 @@
 package client
 
 import (
+	"fmt"
+
+	"github.com/CoreumFoundation/coreum/v3/pkg/config"
 
 	// cosmos-sdk
 	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
@@ -15,22 +18,42 @@ import (
 
 // Client implements a wrapper around both Tendermint RPC HTTP client and
 // Cosmos SDK REST client that allow for essential data queries.
+// The embedded client is the first endpoint. Methods of Client itself are routed
+// to the healthiest of all endpoints and retried on another one when they fail.
 type Client struct {
 	*mblclient.Client
+
+	// Network is the profile of the chain of the nodes, which encodes addresses of the chain.
+	Network custom.Network
+
+	endpoints []*endpoint
 }
 
 // NewClient creates a new client with the given configuration and
 // return Client struct. An error is returned if it fails.
-func NewClient(cfg *mblconfig.ClientConfig) *Client {
+// Fallbacks are the configurations of other nodes of the same chain.
+func NewClient(cfg *mblconfig.ClientConfig, fallbacks ...*mblconfig.ClientConfig) *Client {
+	cfgs := append([]*mblconfig.ClientConfig{cfg}, fallbacks...)
+
+	endpoints := make([]*endpoint, len(cfgs))
+	for i, cfg := range cfgs {
+		endpoints[i] = &endpoint{Client: newNodeClient(cfg, custom.EncodingConfig), cfg: cfg, name: fmt.Sprintf("endpoint-%d", i)}
+	}
+
+	return &Client{Client: endpoints[0].Client, Network: custom.CurrentNetwork, endpoints: endpoints}
+}
+
+// newNodeClient creates a client of a single node which decodes transactions with encCfg.
+func newNodeClient(cfg *mblconfig.ClientConfig, encCfg config.EncodingConfig) *mblclient.Client {
 	client := mblclient.NewClient(cfg)
 
 	client.CliCtx.Context = client.CliCtx.Context.
 		// WithCodec(custom.EncodingConfig.Marshaler).
-		WithCodec(custom.EncodingConfig.Codec).
-		WithLegacyAmino(custom.EncodingConfig.Amino).
-		WithTxConfig(custom.EncodingConfig.TxConfig).
-		WithInterfaceRegistry(custom.EncodingConfig.InterfaceRegistry).
+		WithCodec(encCfg.Codec).
+		WithLegacyAmino(encCfg.Amino).
+		WithTxConfig(encCfg.TxConfig).
+		WithInterfaceRegistry(encCfg.InterfaceRegistry).
 		WithAccountRetriever(authtypes.AccountRetriever{})
 
-	return &Client{client}
+	return client
 }
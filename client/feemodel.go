@@ -0,0 +1,21 @@
+package client
+
+import (
+	"context"
+
+	feemodeltypes "github.com/CoreumFoundation/coreum/v3/x/feemodel/types"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+)
+
+// GetMinGasPrice returns the current minimum gas price of the feemodel module.
+func (c *Client) GetMinGasPrice(ctx context.Context) (price sdktypes.DecCoin, err error) {
+	err = c.do("GetMinGasPrice", func(e *endpoint) error {
+		res, err := feemodeltypes.NewQueryClient(e.CliCtx.Context).MinGasPrice(ctx, &feemodeltypes.QueryMinGasPriceRequest{})
+		if err != nil {
+			return err
+		}
+		price = res.MinGasPrice
+		return nil
+	})
+	return price, err
+}
@@ -0,0 +1,72 @@
+package client
+
+import (
+	"context"
+
+	// cosmos-sdk
+	v1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	v1beta1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1beta1"
+)
+
+// GetProposals_v1 returns the page of gov v1 proposals starting at nextKey.
+func (c *Client) GetProposals_v1(ctx context.Context, nextKey []byte) (res *v1.QueryProposalsResponse, err error) {
+	err = c.do("GetProposals_v1", func(e *endpoint) error {
+		res, err = e.GRPC.GetProposals_v1(ctx, nextKey)
+		return err
+	})
+	return res, err
+}
+
+// GetProposal_v1 returns the gov v1 proposal of the id.
+func (c *Client) GetProposal_v1(ctx context.Context, id uint64) (p *v1.Proposal, err error) {
+	err = c.do("GetProposal_v1", func(e *endpoint) error {
+		p, err = e.GRPC.GetProposal_v1(ctx, id)
+		return err
+	})
+	return p, err
+}
+
+// GetProposalTallyResult_v1 returns the tally of the gov v1 proposal of the id.
+func (c *Client) GetProposalTallyResult_v1(ctx context.Context, id uint64) (tally *v1.TallyResult, err error) {
+	err = c.do("GetProposalTallyResult_v1", func(e *endpoint) error {
+		tally, err = e.GRPC.GetProposalTallyResult_v1(ctx, id)
+		return err
+	})
+	return tally, err
+}
+
+// GetNumberofProposals_v1 returns the number of gov v1 proposals.
+func (c *Client) GetNumberofProposals_v1() (count uint64, err error) {
+	err = c.do("GetNumberofProposals_v1", func(e *endpoint) error {
+		count, err = e.GRPC.GetNumberofProposals_v1()
+		return err
+	})
+	return count, err
+}
+
+// GetProposals_v1beta1 returns the page of gov v1beta1 proposals starting at nextKey.
+func (c *Client) GetProposals_v1beta1(ctx context.Context, nextKey []byte) (res *v1beta1.QueryProposalsResponse, err error) {
+	err = c.do("GetProposals_v1beta1", func(e *endpoint) error {
+		res, err = e.GRPC.GetProposals_v1beta1(ctx, nextKey)
+		return err
+	})
+	return res, err
+}
+
+// GetProposal_v1beta1 returns the gov v1beta1 proposal of the id.
+func (c *Client) GetProposal_v1beta1(ctx context.Context, id uint64) (p *v1beta1.Proposal, err error) {
+	err = c.do("GetProposal_v1beta1", func(e *endpoint) error {
+		p, err = e.GRPC.GetProposal_v1beta1(ctx, id)
+		return err
+	})
+	return p, err
+}
+
+// GetProposalTallyResult_v1beta1 returns the tally of the gov v1beta1 proposal of the id.
+func (c *Client) GetProposalTallyResult_v1beta1(ctx context.Context, id uint64) (tally *v1beta1.TallyResult, err error) {
+	err = c.do("GetProposalTallyResult_v1beta1", func(e *endpoint) error {
+		tally, err = e.GRPC.GetProposalTallyResult_v1beta1(ctx, id)
+		return err
+	})
+	return tally, err
+}
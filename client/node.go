@@ -0,0 +1,144 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/CoreumFoundation/coreum/v3/pkg/config"
+	sdkclient "github.com/cosmos/cosmos-sdk/client"
+
+	tmctypes "github.com/cometbft/cometbft/rpc/core/types"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+)
+
+// GetLatestBlockHeight returns the latest block height of the healthiest endpoint, -1 when every endpoint failed.
+func (c *Client) GetLatestBlockHeight() (int64, error) {
+	var height int64
+	err := c.do("GetLatestBlockHeight", func(e *endpoint) error {
+		h, err := e.RPC.GetLatestBlockHeight()
+		if h == -1 {
+			if err == nil {
+				err = fmt.Errorf("failed to get latest block height")
+			}
+			return err
+		}
+		e.setHeight(h)
+		height = h
+		return nil
+	})
+	if err != nil {
+		return -1, err
+	}
+	return height, nil
+}
+
+// GetStatus returns the status of the healthiest endpoint.
+func (c *Client) GetStatus() (status *tmctypes.ResultStatus, err error) {
+	err = c.do("GetStatus", func(e *endpoint) error {
+		status, err = e.RPC.GetStatus()
+		if err == nil {
+			e.setHeight(status.SyncInfo.LatestBlockHeight)
+			e.setEarliest(status.SyncInfo.EarliestBlockHeight)
+		}
+		return err
+	})
+	return status, err
+}
+
+// GetBlock returns the block at the height.
+func (c *Client) GetBlock(height int64) (block *tmctypes.ResultBlock, err error) {
+	err = c.doAt("GetBlock", height, func(e *endpoint) error {
+		block, err = e.RPC.GetBlock(height)
+		return err
+	})
+	return block, err
+}
+
+// GetValidatorsInHeight returns the validator set at the height.
+func (c *Client) GetValidatorsInHeight(height int64) (vals *tmctypes.ResultValidators, err error) {
+	err = c.doAt("GetValidatorsInHeight", height, func(e *endpoint) error {
+		vals, err = e.RPC.GetValidatorsInHeight(height)
+		return err
+	})
+	return vals, err
+}
+
+// GetBlockAndTxsFromNode returns the block at the height and its transactions decoded with encCfg.
+func (c *Client) GetBlockAndTxsFromNode(encCfg config.EncodingConfig, height int64) (block *tmctypes.ResultBlock, txs []*sdktypes.TxResponse, err error) {
+	err = c.doAt("GetBlockAndTxsFromNode", height, func(e *endpoint) error {
+		block, txs, err = e.decoder(encCfg).RPC.GetBlockAndTxsFromNode(encCfg.Codec, height)
+		return err
+	})
+	return block, txs, err
+}
+
+// GetAccount returns the account of the address.
+// The address is queried as is, so that addresses of a network other than the one of the sdk config are accepted.
+func (c *Client) GetAccount(address string) (acc sdkclient.Account, err error) {
+	err = c.do("GetAccount", func(e *endpoint) error {
+		res, err := authtypes.NewQueryClient(e.CliCtx.Context).Account(context.Background(), &authtypes.QueryAccountRequest{Address: address})
+		if err != nil {
+			return err
+		}
+		var account authtypes.AccountI
+		if err := e.CliCtx.InterfaceRegistry.UnpackAny(res.Account, &account); err != nil {
+			return fmt.Errorf("failed to unpack account %s : %s", address, err)
+		}
+		acc = account
+		return nil
+	})
+	return acc, err
+}
+
+// GetNetworkChainID returns the chain id reported by the healthiest endpoint.
+func (c *Client) GetNetworkChainID() (chainID string, err error) {
+	err = c.do("GetNetworkChainID", func(e *endpoint) error {
+		chainID, err = e.RPC.GetNetworkChainID()
+		return err
+	})
+	return chainID, err
+}
+
+// GetTx returns the transaction of the hash decoded with encCfg.
+// Pruned nodes drop old transactions, so a transaction they do not find is looked up on archive endpoints.
+func (c *Client) GetTx(encCfg config.EncodingConfig, hash string) (tx *sdktypes.TxResponse, err error) {
+	var answered *endpoint
+	fn := func(e *endpoint) error {
+		answered = e
+		tx, err = e.decoder(encCfg).CliCtx.GetTx(hash)
+		return err
+	}
+
+	err = c.do("GetTx", fn)
+	if err != nil && notFound(err) && !answered.archive {
+		err = c.doArchive("GetTx", err, fn)
+	}
+	return tx, err
+}
+
+// Block returns the block at the height, the latest block when height is nil.
+func (c *Client) Block(ctx context.Context, height *int64) (block *tmctypes.ResultBlock, err error) {
+	err = c.doAt("Block", heightOf(height), func(e *endpoint) error {
+		block, err = e.RPC.Block(ctx, height)
+		return err
+	})
+	return block, err
+}
+
+// BlockResults returns the results of the block at the height, of the latest block when height is nil.
+func (c *Client) BlockResults(ctx context.Context, height *int64) (results *tmctypes.ResultBlockResults, err error) {
+	err = c.doAt("BlockResults", heightOf(height), func(e *endpoint) error {
+		results, err = e.RPC.BlockResults(ctx, height)
+		return err
+	})
+	return results, err
+}
+
+// heightOf returns the height of an optional height parameter, 0 for the latest height.
+func heightOf(height *int64) int64 {
+	if height == nil {
+		return 0
+	}
+	return *height
+}
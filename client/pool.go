@@ -0,0 +1,402 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/CoreumFoundation/coreum/v3/pkg/config"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/cosmostation/cosmostation-coreum/custom"
+
+	//mbl
+	mblclient "github.com/cosmostation/mintscan-backend-library/client"
+	mblconfig "github.com/cosmostation/mintscan-backend-library/config"
+)
+
+var (
+	// endpointMaxLag is how far an endpoint may fall behind the highest reported height and still be preferred.
+	endpointMaxLag = int64(2)
+
+	// probeInterval is the interval between probes of the latest block height of every endpoint.
+	probeInterval = 10 * time.Second
+
+	// ewmaWeight is the weight of the latest observation in latency and error rate.
+	ewmaWeight = 0.2
+)
+
+// endpoint is a node with the statistics used to route calls to it.
+type endpoint struct {
+	*mblclient.Client
+	cfg  *mblconfig.ClientConfig
+	name string
+
+	// archive is set for nodes which keep history. They serve heights pruned nodes do not keep.
+	archive bool
+
+	mu        sync.Mutex
+	latency   time.Duration // moving average of the latency of calls
+	errorRate float64       // moving average of failed calls, from 0 to 1
+	height    int64         // latest block height reported by the node
+	earliest  int64         // earliest block height kept by the node, 0 until the node is probed
+
+	// legacy are clients of the node which decode transactions with the encoding config of an older release.
+	legacy map[codectypes.InterfaceRegistry]*mblclient.Client
+}
+
+// decoder returns the client of the node which decodes transactions with encCfg.
+// Clients of older releases are created on first use and kept.
+func (e *endpoint) decoder(encCfg config.EncodingConfig) *mblclient.Client {
+	if e.cfg == nil || encCfg.InterfaceRegistry == e.CliCtx.Context.InterfaceRegistry {
+		return e.Client
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	c, ok := e.legacy[encCfg.InterfaceRegistry]
+	if !ok {
+		if e.legacy == nil {
+			e.legacy = make(map[codectypes.InterfaceRegistry]*mblclient.Client)
+		}
+		c = newNodeClient(e.cfg, encCfg)
+		e.legacy[encCfg.InterfaceRegistry] = c
+	}
+	return c
+}
+
+// observe records the latency and the result of a call.
+func (e *endpoint) observe(d time.Duration, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	failed := 0.0
+	if err != nil {
+		failed = 1.0
+	}
+	e.errorRate += ewmaWeight * (failed - e.errorRate)
+
+	if err == nil {
+		if e.latency == 0 {
+			e.latency = d
+		} else {
+			e.latency += time.Duration(ewmaWeight * float64(d-e.latency))
+		}
+	}
+}
+
+// setHeight records the latest block height reported by the node.
+func (e *endpoint) setHeight(h int64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if h > e.height {
+		e.height = h
+	}
+}
+
+// setEarliest records the earliest block height kept by the node.
+func (e *endpoint) setEarliest(h int64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.earliest = h
+}
+
+// EndpointStatus is the statistics of an endpoint.
+type EndpointStatus struct {
+	Name      string
+	Archive   bool
+	Latency   time.Duration
+	ErrorRate float64
+	Height    int64
+	Earliest  int64
+}
+
+func (e *endpoint) status() EndpointStatus {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return EndpointStatus{
+		Name:      e.name,
+		Archive:   e.archive,
+		Latency:   e.latency,
+		ErrorRate: e.errorRate,
+		Height:    e.height,
+		Earliest:  e.earliest,
+	}
+}
+
+// serves reports whether the endpoint keeps the block at the height.
+// Endpoints which are not probed yet are assumed to keep every height.
+func (s EndpointStatus) serves(height int64) bool {
+	return s.Earliest == 0 || height >= s.Earliest
+}
+
+// score is lower for a healthier endpoint. Errors weigh more than latency.
+func (s EndpointStatus) score() float64 {
+	latency := float64(s.Latency)
+	if latency == 0 {
+		latency = float64(time.Millisecond)
+	}
+	return latency * (1 + 10*s.ErrorRate)
+}
+
+// rankAt orders endpoints for a call at the height, 0 for calls about the latest state.
+// Endpoints which keep the height come first, and among them pruned nodes come before archive nodes,
+// so that archive nodes only serve what pruned nodes no longer keep. Each group is ordered by rank.
+func rankAt(statuses []EndpointStatus, height int64) []int {
+	order := rank(statuses)
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := statuses[order[i]], statuses[order[j]]
+		if height > 0 && a.serves(height) != b.serves(height) {
+			return a.serves(height)
+		}
+		return !a.Archive && b.Archive
+	})
+	return order
+}
+
+// rank orders endpoints by health. Endpoints which are caught up with the highest reported height come first.
+func rank(statuses []EndpointStatus) []int {
+	var tip int64
+	for _, s := range statuses {
+		if s.Height > tip {
+			tip = s.Height
+		}
+	}
+
+	order := make([]int, len(statuses))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := statuses[order[i]], statuses[order[j]]
+		aBehind, bBehind := tip-a.Height > endpointMaxLag, tip-b.Height > endpointMaxLag
+		if aBehind != bBehind {
+			return !aBehind
+		}
+		return a.score() < b.score()
+	})
+	return order
+}
+
+// Endpoints returns the statistics of every endpoint in the order calls are routed.
+func (c *Client) Endpoints() []EndpointStatus {
+	statuses := make([]EndpointStatus, len(c.endpoints))
+	for i, e := range c.endpoints {
+		statuses[i] = e.status()
+	}
+
+	ranked := make([]EndpointStatus, 0, len(statuses))
+	for _, i := range rankAt(statuses, 0) {
+		ranked = append(ranked, statuses[i])
+	}
+	return ranked
+}
+
+// do calls fn with the healthiest endpoint for the latest state and retries on the next one when it fails transiently.
+// Other errors are returned as is. The error of the last endpoint is returned when every endpoint failed.
+func (c *Client) do(method string, fn func(e *endpoint) error) error {
+	return c.doAt(method, 0, fn)
+}
+
+// doAt calls fn with the healthiest endpoint which keeps the height and retries on the next one when it fails transiently
+// or answers that it pruned the height. Archive endpoints come last, so they serve the heights pruned nodes dropped.
+func (c *Client) doAt(method string, height int64, fn func(e *endpoint) error) error {
+	statuses := make([]EndpointStatus, len(c.endpoints))
+	for i, e := range c.endpoints {
+		statuses[i] = e.status()
+	}
+
+	var err error
+	for _, i := range rankAt(statuses, height) {
+		e := c.endpoints[i]
+
+		begin := time.Now()
+		err = fn(e)
+		if lowest, ok := pruned(err); ok {
+			// the endpoint answered that it no longer keeps the height, an endpoint which keeps history may
+			e.observe(time.Since(begin), nil)
+			e.setEarliest(lowest)
+			if len(c.endpoints) > 1 {
+				zap.S().Infof("%s failed on %s, will retry on an archive endpoint : %s", method, e.name, err)
+			}
+			continue
+		}
+		if err != nil && !transient(err) {
+			// the endpoint answered, e.g. not found or invalid height, and every other endpoint would answer the same
+			e.observe(time.Since(begin), nil)
+			return err
+		}
+		e.observe(time.Since(begin), err)
+		if err == nil {
+			return nil
+		}
+		if len(c.endpoints) > 1 {
+			zap.S().Infof("%s failed on %s, will retry on another endpoint : %s", method, e.name, err)
+		}
+	}
+
+	return err
+}
+
+// doArchive calls fn with the archive endpoints in the order of their health, until one of them answers.
+// It returns err when there is no archive endpoint.
+func (c *Client) doArchive(method string, err error, fn func(e *endpoint) error) error {
+	statuses := make([]EndpointStatus, len(c.endpoints))
+	for i, e := range c.endpoints {
+		statuses[i] = e.status()
+	}
+
+	for _, i := range rank(statuses) {
+		e := c.endpoints[i]
+		if !e.archive {
+			continue
+		}
+
+		begin := time.Now()
+		err = fn(e)
+		if err != nil && !transient(err) {
+			e.observe(time.Since(begin), nil)
+			return err
+		}
+		e.observe(time.Since(begin), err)
+		if err == nil {
+			return nil
+		}
+		zap.S().Infof("%s failed on %s, will retry on another archive endpoint : %s", method, e.name, err)
+	}
+
+	return err
+}
+
+var (
+	// transientCodes are the gRPC codes of failures of the endpoint rather than of the request.
+	transientCodes = map[codes.Code]bool{
+		codes.Unavailable:       true,
+		codes.DeadlineExceeded:  true,
+		codes.ResourceExhausted: true,
+		codes.Aborted:           true,
+	}
+
+	// grpcCodePattern matches the code of a gRPC error which lost its status when it was wrapped.
+	grpcCodePattern = regexp.MustCompile(`rpc error: code = (\w+)`)
+
+	// prunedPattern matches the error of a node which pruned the requested height.
+	prunedPattern = regexp.MustCompile(`height \d+ is not available, lowest height is (\d+)`)
+
+	// httpStatusPattern matches the status of an HTTP response in errors of the RPC client.
+	httpStatusPattern = regexp.MustCompile(`Status: (\d{3})`)
+)
+
+// transient reports whether err is a failure of the endpoint which another endpoint may not have:
+// network errors, timeouts, unavailable gRPC servers and HTTP 5xx responses.
+func transient(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	if s, ok := status.FromError(err); ok {
+		return transientCodes[s.Code()]
+	}
+
+	msg := err.Error()
+	if m := grpcCodePattern.FindStringSubmatch(msg); m != nil {
+		for code := range transientCodes {
+			if code.String() == m[1] {
+				return true
+			}
+		}
+		return false
+	}
+	if m := httpStatusPattern.FindStringSubmatch(msg); m != nil {
+		return m[1][0] == '5'
+	}
+	return false
+}
+
+// notFound reports whether err is the answer of a node which does not have the requested data.
+func notFound(err error) bool {
+	if s, ok := status.FromError(err); ok {
+		return s.Code() == codes.NotFound
+	}
+	if m := grpcCodePattern.FindStringSubmatch(err.Error()); m != nil {
+		return m[1] == codes.NotFound.String()
+	}
+	return strings.Contains(err.Error(), "not found")
+}
+
+// pruned reports whether err is the answer of a node which no longer keeps the requested height,
+// and returns the lowest height the node keeps. Such nodes answer with HTTP 200.
+func pruned(err error) (int64, bool) {
+	if err == nil {
+		return 0, false
+	}
+	m := prunedPattern.FindStringSubmatch(err.Error())
+	if m == nil {
+		return 0, false
+	}
+	lowest, perr := strconv.ParseInt(m[1], 10, 64)
+	if perr != nil {
+		return 0, false
+	}
+	return lowest, true
+}
+
+// AddArchiveNodes adds endpoints of nodes which keep history. They serve heights
+// the other endpoints have pruned, and calls about the latest state only when the other endpoints fail.
+func (c *Client) AddArchiveNodes(cfgs ...*mblconfig.ClientConfig) {
+	for _, cfg := range cfgs {
+		c.endpoints = append(c.endpoints, &endpoint{
+			Client:  newNodeClient(cfg, custom.EncodingConfig),
+			cfg:     cfg,
+			name:    fmt.Sprintf("archive-%d", len(c.endpoints)),
+			archive: true,
+		})
+	}
+}
+
+// Monitor probes the latest and earliest block heights of every endpoint until ctx is canceled,
+// so that calls are not routed to endpoints which stopped following the chain or pruned the height.
+func (c *Client) Monitor(ctx context.Context) {
+	if len(c.endpoints) <= 1 {
+		return
+	}
+
+	t := time.NewTicker(probeInterval)
+	defer t.Stop()
+
+	for {
+		for _, e := range c.endpoints {
+			begin := time.Now()
+			status, err := e.RPC.GetStatus()
+			e.observe(time.Since(begin), err)
+			if err == nil {
+				e.setHeight(status.SyncInfo.LatestBlockHeight)
+				e.setEarliest(status.SyncInfo.EarliestBlockHeight)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
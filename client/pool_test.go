@@ -0,0 +1,157 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRankEndpoints(t *testing.T) {
+	statuses := []EndpointStatus{
+		{Name: "slow", Latency: 300 * time.Millisecond, Height: 100},
+		{Name: "behind", Latency: 10 * time.Millisecond, Height: 90},
+		{Name: "failing", Latency: 50 * time.Millisecond, ErrorRate: 0.9, Height: 100},
+		{Name: "fast", Latency: 50 * time.Millisecond, Height: 99},
+	}
+
+	var names []string
+	for _, i := range rank(statuses) {
+		names = append(names, statuses[i].Name)
+	}
+	require.Equal(t, []string{"fast", "slow", "failing", "behind"}, names)
+}
+
+func TestRankEndpointsAtHeight(t *testing.T) {
+	statuses := []EndpointStatus{
+		{Name: "archive", Archive: true, Latency: 500 * time.Millisecond, Height: 100, Earliest: 1},
+		{Name: "pruned", Latency: 50 * time.Millisecond, Height: 100, Earliest: 80},
+		{Name: "unprobed", Latency: 10 * time.Millisecond},
+	}
+
+	names := func(height int64) (names []string) {
+		for _, i := range rankAt(statuses, height) {
+			names = append(names, statuses[i].Name)
+		}
+		return names
+	}
+
+	require.Equal(t, []string{"pruned", "unprobed", "archive"}, names(0))
+	require.Equal(t, []string{"pruned", "unprobed", "archive"}, names(90))
+	require.Equal(t, []string{"unprobed", "archive", "pruned"}, names(50))
+}
+
+func TestEndpointObserve(t *testing.T) {
+	e := new(endpoint)
+	e.observe(100*time.Millisecond, nil)
+	require.Equal(t, 100*time.Millisecond, e.latency)
+	require.Zero(t, e.errorRate)
+
+	e.observe(time.Second, errors.New("connection refused"))
+	require.Equal(t, 100*time.Millisecond, e.latency)
+	require.InDelta(t, ewmaWeight, e.errorRate, 1e-9)
+}
+
+func TestTransient(t *testing.T) {
+	require.True(t, transient(context.DeadlineExceeded))
+	require.True(t, transient(&net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}))
+	require.True(t, transient(status.Error(codes.Unavailable, "connection closed")))
+	require.True(t, transient(fmt.Errorf("failed to query : %w", status.Error(codes.ResourceExhausted, "too many requests"))))
+	require.True(t, transient(fmt.Errorf("failed to query : %s", status.Error(codes.Unavailable, "connection closed"))))
+	require.True(t, transient(errors.New("error in json rpc client, with http response metadata: (Status: 502 Bad Gateway, Protocol HTTP/1.1)")))
+
+	require.False(t, transient(status.Error(codes.NotFound, "proposal 1 doesn't exist")))
+	require.False(t, transient(fmt.Errorf("failed to query : %s", status.Error(codes.InvalidArgument, "invalid height"))))
+	require.False(t, transient(errors.New("error in json rpc client, with http response metadata: (Status: 404 Not Found, Protocol HTTP/1.1)")))
+	require.False(t, transient(errors.New("height 100 must be less than or equal to the current blockchain height 90")))
+}
+
+func TestDoRetriesTransientErrors(t *testing.T) {
+	c := &Client{endpoints: []*endpoint{{name: "first"}, {name: "second"}}}
+
+	var called []string
+	err := c.do("test", func(e *endpoint) error {
+		called = append(called, e.name)
+		if e.name == "first" {
+			return status.Error(codes.Unavailable, "connection closed")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"first", "second"}, called)
+	require.InDelta(t, ewmaWeight, c.endpoints[0].errorRate, 1e-9)
+}
+
+func TestDoReturnsOtherErrors(t *testing.T) {
+	c := &Client{endpoints: []*endpoint{{name: "first"}, {name: "second"}}}
+
+	var called []string
+	err := c.do("test", func(e *endpoint) error {
+		called = append(called, e.name)
+		return status.Error(codes.NotFound, "proposal 1 doesn't exist")
+	})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	require.Equal(t, []string{"first"}, called)
+	require.Zero(t, c.endpoints[0].errorRate)
+}
+
+func TestDoAtRetriesPrunedHeightsOnArchive(t *testing.T) {
+	c := &Client{endpoints: []*endpoint{{name: "pruned"}, {name: "archive", archive: true}}}
+
+	var called []string
+	err := c.doAt("test", 50, func(e *endpoint) error {
+		called = append(called, e.name)
+		if !e.archive {
+			return errors.New("RPC error -32603 - Internal error: height 50 is not available, lowest height is 80")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"pruned", "archive"}, called)
+	require.Zero(t, c.endpoints[0].errorRate)
+	require.Equal(t, int64(80), c.endpoints[0].earliest)
+
+	// the lowest height reported by the pruned node routes the next call straight to the archive
+	called = nil
+	require.NoError(t, c.doAt("test", 60, func(e *endpoint) error {
+		called = append(called, e.name)
+		return nil
+	}))
+	require.Equal(t, []string{"archive"}, called)
+}
+
+func TestDoArchiveAfterNotFound(t *testing.T) {
+	c := &Client{endpoints: []*endpoint{{name: "pruned"}, {name: "archive", archive: true}}}
+
+	var called []string
+	var answered *endpoint
+	fn := func(e *endpoint) error {
+		answered = e
+		called = append(called, e.name)
+		if !e.archive {
+			return fmt.Errorf("failed to query : %s", status.Error(codes.NotFound, "tx not found: ABCD"))
+		}
+		return nil
+	}
+
+	err := c.do("test", fn)
+	require.True(t, notFound(err))
+	require.False(t, answered.archive)
+	require.NoError(t, c.doArchive("test", err, fn))
+	require.Equal(t, []string{"pruned", "archive"}, called)
+
+	// without archive endpoints the answer of the pruned node is returned
+	c = &Client{endpoints: []*endpoint{{name: "pruned"}}}
+	require.Equal(t, err, c.doArchive("test", err, fn))
+
+	require.True(t, notFound(status.Error(codes.NotFound, "tx not found")))
+	require.True(t, notFound(errors.New("RPC error -32603 - Internal error: tx (ABCD) not found")))
+	require.False(t, notFound(status.Error(codes.InvalidArgument, "invalid hash")))
+}
@@ -19,7 +19,11 @@ import (
 // 필요한 함수를 우선 모듈에 맞게 정의한 후, 나중에 코어로 이전
 // 코어로 분리가 가능할 것 같다.
 func (c *Client) GetValidatorsByStatus(ctx context.Context, status stakingtypes.BondStatus) (validators []mdschema.Validator, err error) {
-	res, err := c.GRPC.GetValidatorsByStatus(ctx, status, 2000)
+	var res *stakingtypes.QueryValidatorsResponse
+	err = c.do("GetValidatorsByStatus", func(e *endpoint) error {
+		res, err = e.GRPC.GetValidatorsByStatus(ctx, status, 2000)
+		return err
+	})
 	if err != nil {
 		return []mdschema.Validator{}, nil
 	}
@@ -29,7 +33,7 @@ func (c *Client) GetValidatorsByStatus(ctx context.Context, status stakingtypes.
 	}
 
 	for i, val := range res.Validators {
-		accAddr, err := mbltypes.ConvertAccAddrFromValAddr(val.OperatorAddress)
+		accAddr, err := c.Network.ConvertAccAddrFromValAddr(val.OperatorAddress)
 		if err != nil {
 			return []mdschema.Validator{}, fmt.Errorf("failed to convert address from validator Address : %s", err)
 		}
@@ -37,7 +41,7 @@ func (c *Client) GetValidatorsByStatus(ctx context.Context, status stakingtypes.
 		var conspubkey cryptotypes.PubKey
 		custom.AppCodec.UnpackAny(val.ConsensusPubkey, &conspubkey)
 
-		valconspub, err := sdktypes.Bech32ifyAddressBytes(sdktypes.GetConfig().GetBech32ConsensusPubPrefix(), conspubkey.Bytes())
+		valconspub, err := sdktypes.Bech32ifyAddressBytes(c.Network.ConsensusPubPrefix(), conspubkey.Bytes())
 		if err != nil {
 			return []mdschema.Validator{}, fmt.Errorf("failed to get consesnsus pubkey : %s", err)
 		}
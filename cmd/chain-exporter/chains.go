@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/cosmostation/cosmostation-coreum/app"
+	"github.com/cosmostation/cosmostation-coreum/custom"
+	"github.com/cosmostation/cosmostation-coreum/exporter"
+	"github.com/cosmostation/cosmostation-coreum/health"
+	"github.com/cosmostation/cosmostation-coreum/sink"
+)
+
+// newChainExporters returns exporters of the chains whose config files are given.
+// Each chain has its own node client, cursor, network profile and chain schemas, while the publisher of ex is shared.
+// The sink of ex is shared unless it writes to the databases, which each chain writes to its own schemas.
+// wsEndpoints and networks are matched with names by position. Chains without an endpoint poll their node,
+// and chains without a profile use the network of ex.
+func newChainExporters(ex *exporter.Exporter, names, wsEndpoints []string, networks []custom.Network) []*exporter.Exporter {
+	exporters := make([]*exporter.Exporter, 0, len(names))
+	for i, name := range names {
+		network := ex.Network
+		if i < len(networks) {
+			network = networks[i]
+		}
+		c := exporter.NewExporter(app.NewChainApp(ex.App, name, network))
+		c.SetChainID()
+		if _, ok := ex.Sink.(*sink.Postgres); !ok {
+			c.Sink = ex.Sink
+		}
+		c.InitialHeight = 0
+		c.Publisher, c.EventSubject = ex.Publisher, ex.EventSubject
+		c.WSEndpoint = ""
+		c.AggregateFees = false
+		if i < len(wsEndpoints) {
+			c.WSEndpoint = wsEndpoints[i]
+		}
+		exporters = append(exporters, c)
+	}
+	return exporters
+}
+
+// start runs the exporter of every chain until ctx is canceled.
+func start(ctx context.Context, exporters []*exporter.Exporter, op int) {
+	wg := new(sync.WaitGroup)
+	for _, ex := range exporters {
+		wg.Add(1)
+		go func(ex *exporter.Exporter) {
+			defer wg.Done()
+			ex.Start(ctx, op)
+		}(ex)
+	}
+	wg.Wait()
+}
+
+// chainChecks merges the checks of every exporter.
+// Names are prefixed with the chain-id when more than one chain is indexed.
+func chainChecks(exporters []*exporter.Exporter, checks func(ex *exporter.Exporter) health.Checks) health.Checks {
+	if len(exporters) == 1 {
+		return checks(exporters[0])
+	}
+
+	merged := make(health.Checks)
+	for _, ex := range exporters {
+		for name, check := range checks(ex) {
+			merged[ex.Config.Chain.ChainID+"/"+name] = check
+		}
+	}
+	return merged
+}
+
+// parseChainNetworks returns the predefined network profiles of the names separated by commas.
+func parseChainNetworks(s string) ([]custom.Network, error) {
+	var networks []custom.Network
+	for _, name := range strings.Split(s, ",") {
+		n, err := custom.LookupNetwork(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		networks = append(networks, n)
+	}
+	return networks, nil
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// serveHTTP starts the HTTP server for operational endpoints such as metrics on the given port.
+// It returns a function which gracefully shuts the server down. The server is not started when port is empty.
+func serveHTTP(port string, handler http.Handler) (shutdown func()) {
+	if port == "" {
+		return func() {}
+	}
+
+	sm := &http.Server{
+		Addr:         ":" + port,
+		Handler:      handler,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		zap.S().Infof("HTTP server is running on http://localhost:%s", port)
+		if err := sm.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			zap.S().Errorf("failed to serve HTTP: %s", err)
+		}
+	}()
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := sm.Shutdown(ctx); err != nil {
+			zap.S().Errorf("failed to shut down HTTP server: %s", err)
+		}
+	}
+}
@@ -2,13 +2,18 @@ package main
 
 import (
 	"context"
+	"flag"
+	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/cosmostation/cosmostation-coreum/app"
+	"github.com/cosmostation/cosmostation-coreum/custom"
+	"github.com/cosmostation/cosmostation-coreum/health"
 	"github.com/cosmostation/cosmostation-coreum/mintscan"
 	commonhandler "github.com/cosmostation/cosmostation-coreum/mintscan/common"
 
@@ -23,20 +28,70 @@ var (
 )
 
 func main() {
+	nodeFallbacks := flag.String("node-fallbacks", "", "comma separated base names of config files of other nodes to fail over to (e.g. mintscan-node2,mintscan-node3)")
+	archiveNodes := flag.String("archive-nodes", "", "comma separated base names of config files of archive nodes serving heights pruned nodes no longer keep (e.g. mintscan-archive)")
+	chainLineage := flag.String("chain-lineage", "", "chain-ids of the network across hard forks as chain-id:start-end separated by commas, the end of the last one may be omitted (e.g. coreum-mainnet-1:1-1000,coreum-mainnet-2:1001-)")
+	network := flag.String("network", "testnet", "network profile \n  - mainnet, testnet, devnet : predefined profiles of coreum\n  - custom : profile of --network-chain-id, --address-prefix, --coin-type, --bond-denom and --power-reduction")
+	networkChainID := flag.String("network-chain-id", "", "chain-id the node must report (custom network), not checked when empty")
+	addressPrefix := flag.String("address-prefix", "", "bech32 account address prefix (custom network)")
+	coinType := flag.Uint("coin-type", 990, "bip44 coin type (custom network)")
+	bondDenom := flag.String("bond-denom", "", "bond denom (custom network)")
+	powerReduction := flag.Uint64("power-reduction", 1000000, "amount of bond denom per unit of consensus power (custom network)")
+	flag.Parse()
+
+	log.Println("node-fallbacks :", *nodeFallbacks)
+	log.Println("archive-nodes :", *archiveNodes)
+	log.Println("chain-lineage :", *chainLineage)
+	log.Println("network :", *network, *networkChainID, *addressPrefix, *coinType, *bondDenom, *powerReduction)
+
+	if *nodeFallbacks != "" {
+		app.SetNodeFallbacks(strings.Split(*nodeFallbacks, ","))
+	}
+	if *archiveNodes != "" {
+		app.SetArchiveNodes(strings.Split(*archiveNodes, ","))
+	}
+	if *chainLineage != "" {
+		segments, err := app.ParseChainLineage(*chainLineage)
+		if err != nil {
+			zap.S().Error(err)
+			os.Exit(1)
+		}
+		app.SetChainLineage(segments)
+	}
+
+	n, err := custom.NewNetwork(*network, *networkChainID, *addressPrefix, uint32(*coinType), *bondDenom, *powerReduction)
+	if err == nil {
+		err = custom.SetNetwork(n)
+	}
+	if err != nil {
+		zap.S().Error(err)
+		os.Exit(1)
+	}
+
 	fileBaseName := "mintscan"
 	mApp := app.NewApp(fileBaseName)
+	go mApp.Client.Monitor(context.Background())
 
-	cid, err := mApp.Client.RPC.GetNetworkChainID()
+	cid, err := mApp.Client.GetNetworkChainID()
 
 	zap.S().Info("connected chain-id : ", cid)
 
 	if cid != mApp.Config.Chain.ChainID {
 		panic(err)
 	}
+	// mintscan only reads what the exporter stored, so the chain-id is looked up and never inserted
+	if err := mApp.LoadChainInfo(); err != nil {
+		zap.S().Error(err)
+		mApp.Close()
+		os.Exit(1)
+	}
 
 	r := mux.NewRouter()
-	r = r.PathPrefix("/v1").Subrouter()
-	commonhandler.RegisterHandlers(mApp, r)
+	r.Handle("/healthz", health.Handler(health.Checks{})).Methods("GET")
+	r.Handle("/readyz", health.Handler(mintscan.ReadinessChecks(mApp))).Methods("GET")
+
+	v1 := r.PathPrefix("/v1").Subrouter()
+	commonhandler.RegisterHandlers(mApp, v1)
 
 	sm := &http.Server{
 		Addr:         ":" + mApp.Config.Web.Port,
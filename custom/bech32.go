@@ -4,49 +4,59 @@ import (
 	"fmt"
 	"log"
 
-	"github.com/CoreumFoundation/coreum/v3/pkg/config/constant"
 	sdktypes "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/address"
 	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 )
 
+// sealed is set once the sdk config is sealed by SetAppConfig.
+var sealed bool
+
 func init() {
-	SetAppConfig()
+	applyNetwork(sdktypes.GetConfig())
 	if !IsSetAppConfig() {
 		panic(fmt.Errorf("bech32 is not set corretly"))
 	}
 	log.Println("Current bech32 : ", sdktypes.GetConfig())
 }
 
+// IsSetAppConfig reports whether the sdk config uses the address prefix of the current network.
 func IsSetAppConfig() bool {
-	/* 체인 별 Bech32PrefixAccAddr을 비교하도록 코드를 변경 해야한다 */
-	if sdktypes.GetConfig().GetBech32AccountAddrPrefix() != constant.AddressPrefixTest {
+	if sdktypes.GetConfig().GetBech32AccountAddrPrefix() != CurrentNetwork.AddressPrefix {
 		log.Println("bech32 is not identical, will set config ")
 		return false
 	}
 	return true
 }
 
-// SetAppConfig creates a new config instance for the SDK configuration.
-// 안써도 지우지 않는 이유는 다른 네트워크에서 이 함수를 사용하기 때문
+// SetAppConfig applies the current network to the sdk config and seals it.
 func SetAppConfig() {
-	if !IsSetAppConfig() {
-		config := sdktypes.GetConfig()
-		SetBech32AddressPrefixes(config)
-		SetBip44CoinType(config)
-		config.Seal()
+	if sealed {
+		return
 	}
+	config := sdktypes.GetConfig()
+	applyNetwork(config)
+	config.Seal()
+	sealed = true
+	log.Println("Current network : ", CurrentNetwork.Name, sdktypes.GetConfig())
+}
+
+// applyNetwork sets the address prefixes and the coin type of the current network without sealing the config.
+func applyNetwork(config *sdktypes.Config) {
+	SetBech32AddressPrefixes(config)
+	SetBip44CoinType(config)
 }
 
 // SetBech32AddressPrefixes sets the global prefix to be used when serializing addresses to bech32 strings.
 func SetBech32AddressPrefixes(config *sdktypes.Config) {
-	accountPubKeyPrefix := constant.AddressPrefixTest + "pub"
-	validatorAddressPrefix := constant.AddressPrefixTest + "valoper"
-	validatorPubKeyPrefix := constant.AddressPrefixTest + "valoperpub"
-	consNodeAddressPrefix := constant.AddressPrefixTest + "valcons"
-	consNodePubKeyPrefix := constant.AddressPrefixTest + "valconspub"
+	accountAddressPrefix := CurrentNetwork.AddressPrefix
+	accountPubKeyPrefix := accountAddressPrefix + "pub"
+	validatorAddressPrefix := accountAddressPrefix + "valoper"
+	validatorPubKeyPrefix := accountAddressPrefix + "valoperpub"
+	consNodeAddressPrefix := accountAddressPrefix + "valcons"
+	consNodePubKeyPrefix := accountAddressPrefix + "valconspub"
 
-	config.SetBech32PrefixForAccount(constant.AddressPrefixTest, accountPubKeyPrefix)
+	config.SetBech32PrefixForAccount(accountAddressPrefix, accountPubKeyPrefix)
 	config.SetBech32PrefixForValidator(validatorAddressPrefix, validatorPubKeyPrefix)
 	config.SetBech32PrefixForConsensusNode(consNodeAddressPrefix, consNodePubKeyPrefix)
 	config.SetAddressVerifier(func(bytes []byte) error {
@@ -66,5 +76,5 @@ func SetBech32AddressPrefixes(config *sdktypes.Config) {
 
 // SetBip44CoinType sets the global coin type to be used in hierarchical deterministic wallets.
 func SetBip44CoinType(config *sdktypes.Config) {
-	config.SetCoinType(constant.CoinType)
+	config.SetCoinType(CurrentNetwork.CoinType)
 }
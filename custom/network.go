@@ -0,0 +1,147 @@
+package custom
+
+import (
+	"fmt"
+
+	"github.com/CoreumFoundation/coreum/v3/pkg/config/constant"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+)
+
+// network profiles
+const (
+	MAINNET = "mainnet"
+	TESTNET = "testnet"
+	DEVNET  = "devnet"
+	CUSTOM  = "custom"
+)
+
+// Network is the profile of the network being indexed.
+type Network struct {
+	Name           string
+	ChainID        string // chain-id the node must report, not checked when empty
+	AddressPrefix  string
+	CoinType       uint32
+	BondDenom      string
+	PowerReduction sdktypes.Int
+}
+
+var networks = map[string]Network{
+	MAINNET: {
+		Name:           MAINNET,
+		ChainID:        string(constant.ChainIDMain),
+		AddressPrefix:  constant.AddressPrefixMain,
+		CoinType:       constant.CoinType,
+		BondDenom:      constant.DenomMain,
+		PowerReduction: sdktypes.DefaultPowerReduction,
+	},
+	TESTNET: {
+		Name:           TESTNET,
+		ChainID:        string(constant.ChainIDTest),
+		AddressPrefix:  constant.AddressPrefixTest,
+		CoinType:       constant.CoinType,
+		BondDenom:      constant.DenomTest,
+		PowerReduction: sdktypes.DefaultPowerReduction,
+	},
+	DEVNET: {
+		Name:           DEVNET,
+		ChainID:        string(constant.ChainIDDev),
+		AddressPrefix:  constant.AddressPrefixDev,
+		CoinType:       constant.CoinType,
+		BondDenom:      constant.DenomDev,
+		PowerReduction: sdktypes.DefaultPowerReduction,
+	},
+}
+
+// CurrentNetwork is the profile of the network being indexed. It is testnet until SetNetwork is called.
+var CurrentNetwork = networks[TESTNET]
+
+// LookupNetwork returns the predefined profile of the name.
+func LookupNetwork(name string) (Network, error) {
+	n, ok := networks[name]
+	if !ok {
+		return Network{}, fmt.Errorf("unknown network profile : %s", name)
+	}
+	return n, nil
+}
+
+// NewNetwork returns the predefined profile of the name, or a profile of the given values when the name is custom.
+func NewNetwork(name, chainID, addressPrefix string, coinType uint32, bondDenom string, powerReduction uint64) (Network, error) {
+	if name != CUSTOM {
+		return LookupNetwork(name)
+	}
+	return Network{
+		Name:           CUSTOM,
+		ChainID:        chainID,
+		AddressPrefix:  addressPrefix,
+		CoinType:       coinType,
+		BondDenom:      bondDenom,
+		PowerReduction: sdktypes.NewIntFromUint64(powerReduction),
+	}, nil
+}
+
+// SetNetwork sets the profile of the network being indexed. It must be called before SetAppConfig.
+func SetNetwork(n Network) error {
+	if n.AddressPrefix == "" {
+		return fmt.Errorf("address prefix of %s network is empty", n.Name)
+	}
+	if n.PowerReduction.IsNil() || !n.PowerReduction.IsPositive() {
+		return fmt.Errorf("power reduction of %s network must be positive", n.Name)
+	}
+	if sealed {
+		return fmt.Errorf("sdk config is already sealed with %s network", CurrentNetwork.Name)
+	}
+
+	CurrentNetwork = n
+	PowerReduction = n.PowerReduction
+	applyNetwork(sdktypes.GetConfig())
+	return nil
+}
+
+// ValidateChainID returns an error when the chain-id reported by the node belongs to another network.
+func (n Network) ValidateChainID(chainID string) error {
+	if n.ChainID != "" && n.ChainID != chainID {
+		return fmt.Errorf("chain-id of the node %s does not match %s network : %s", chainID, n.Name, n.ChainID)
+	}
+	return nil
+}
+
+// ValidatorAddrPrefix returns the bech32 prefix of validator operator addresses of the network.
+func (n Network) ValidatorAddrPrefix() string {
+	return n.AddressPrefix + "valoper"
+}
+
+// ConsensusPubPrefix returns the bech32 prefix of consensus public keys of the network.
+func (n Network) ConsensusPubPrefix() string {
+	return n.AddressPrefix + "valconspub"
+}
+
+// AccAddress encodes the address bytes as an account address of the network.
+// The sdk config holds the prefix of a single network, so addresses of other networks are encoded with the profile.
+func (n Network) AccAddress(bz []byte) string {
+	if len(bz) == 0 {
+		return ""
+	}
+	addr, err := sdktypes.Bech32ifyAddressBytes(n.AddressPrefix, bz)
+	if err != nil {
+		return ""
+	}
+	return addr
+}
+
+// ConvertValAddrFromAccAddr returns the validator operator address of the account address of the network.
+func (n Network) ConvertValAddrFromAccAddr(accAddr string) (string, error) {
+	bz, err := sdktypes.GetFromBech32(accAddr, n.AddressPrefix)
+	if err != nil {
+		return "", fmt.Errorf("invalid account address %s : %s", accAddr, err)
+	}
+	return sdktypes.Bech32ifyAddressBytes(n.ValidatorAddrPrefix(), bz)
+}
+
+// ConvertAccAddrFromValAddr returns the account address of the validator operator address of the network.
+func (n Network) ConvertAccAddrFromValAddr(valAddr string) (string, error) {
+	bz, err := sdktypes.GetFromBech32(valAddr, n.ValidatorAddrPrefix())
+	if err != nil {
+		return "", fmt.Errorf("invalid validator address %s : %s", valAddr, err)
+	}
+	return sdktypes.Bech32ifyAddressBytes(n.AddressPrefix, bz)
+}
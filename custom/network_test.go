@@ -0,0 +1,49 @@
+package custom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewNetwork(t *testing.T) {
+	n, err := NewNetwork(MAINNET, "", "", 0, "", 0)
+	require.NoError(t, err)
+	require.Equal(t, "core", n.AddressPrefix)
+	require.Equal(t, "ucore", n.BondDenom)
+	require.NoError(t, n.ValidateChainID("coreum-mainnet-1"))
+	require.Error(t, n.ValidateChainID("coreum-testnet-1"))
+
+	n, err = NewNetwork(CUSTOM, "", "cus", 118, "ucus", 1000)
+	require.NoError(t, err)
+	require.Equal(t, int64(1000), n.PowerReduction.Int64())
+	require.NoError(t, n.ValidateChainID("any-chain-1"))
+
+	_, err = NewNetwork("localnet", "", "", 0, "", 0)
+	require.Error(t, err)
+}
+
+func TestNetworkAddresses(t *testing.T) {
+	bz := make([]byte, 20)
+	bz[19] = 1
+
+	mainnet, testnet := networks[MAINNET], networks[TESTNET]
+	acc, test := mainnet.AccAddress(bz), testnet.AccAddress(bz)
+	require.Regexp(t, "^core1", acc)
+	require.Regexp(t, "^testcore1", test)
+
+	val, err := mainnet.ConvertValAddrFromAccAddr(acc)
+	require.NoError(t, err)
+	require.Regexp(t, "^corevaloper1", val)
+	back, err := mainnet.ConvertAccAddrFromValAddr(val)
+	require.NoError(t, err)
+	require.Equal(t, acc, back)
+
+	testVal, err := testnet.ConvertValAddrFromAccAddr(test)
+	require.NoError(t, err)
+	require.Regexp(t, "^testcorevaloper1", testVal)
+
+	// addresses of another network are rejected
+	_, err = mainnet.ConvertValAddrFromAccAddr(test)
+	require.Error(t, err)
+}
@@ -0,0 +1,51 @@
+package custom
+
+import (
+	"sort"
+
+	"github.com/CoreumFoundation/coreum/v3/pkg/config"
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/types/module"
+)
+
+// legacyEncodingConfig is the encoding config of the chain before an upgrade changed registered message types.
+type legacyEncodingConfig struct {
+	chainID       string
+	name          string
+	upgradeHeight int64 // first height decoded with the encoding config of the next upgrade
+	config        config.EncodingConfig
+}
+
+// legacyEncodingConfigs are ordered by upgrade height. Heights after the last upgrade are decoded with EncodingConfig.
+var legacyEncodingConfigs []legacyEncodingConfig
+
+// RegisterLegacyModuleBasics registers module basics of the chain before the upgrade at upgradeHeight,
+// so that transactions of the chain below the height are decoded with the message types valid at that time.
+// Upgrade heights differ between chains, so each chain registers its own upgrades.
+// It must be called before any decoding, e.g. in init.
+func RegisterLegacyModuleBasics(chainID, name string, upgradeHeight int64, basics module.BasicManager) {
+	legacyEncodingConfigs = append(legacyEncodingConfigs, legacyEncodingConfig{
+		chainID:       chainID,
+		name:          name,
+		upgradeHeight: upgradeHeight,
+		config:        config.NewEncodingConfig(basics),
+	})
+	sort.SliceStable(legacyEncodingConfigs, func(i, j int) bool {
+		return legacyEncodingConfigs[i].upgradeHeight < legacyEncodingConfigs[j].upgradeHeight
+	})
+}
+
+// EncodingConfigAt returns the encoding config which was valid at the height of the chain.
+func EncodingConfigAt(chainID string, height int64) config.EncodingConfig {
+	for _, c := range legacyEncodingConfigs {
+		if c.chainID == chainID && height < c.upgradeHeight {
+			return c.config
+		}
+	}
+	return EncodingConfig
+}
+
+// CodecAt returns the codec which was valid at the height of the chain.
+func CodecAt(chainID string, height int64) codec.Codec {
+	return EncodingConfigAt(chainID, height).Codec
+}
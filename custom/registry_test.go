@@ -0,0 +1,27 @@
+package custom
+
+import (
+	"testing"
+
+	chainapp "github.com/CoreumFoundation/coreum/v3/app"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodingConfigAt(t *testing.T) {
+	defer func() { legacyEncodingConfigs = nil }()
+
+	RegisterLegacyModuleBasics("coreum-mainnet-1", "v2", 200, chainapp.ModuleBasics)
+	RegisterLegacyModuleBasics("coreum-mainnet-1", "v1", 100, chainapp.ModuleBasics)
+	RegisterLegacyModuleBasics("coreum-testnet-1", "v1", 500, chainapp.ModuleBasics)
+
+	v1, v2 := legacyEncodingConfigs[0].config, legacyEncodingConfigs[1].config
+	require.Equal(t, "v1", legacyEncodingConfigs[0].name)
+
+	require.Same(t, v1.InterfaceRegistry, EncodingConfigAt("coreum-mainnet-1", 1).InterfaceRegistry)
+	require.Same(t, v1.InterfaceRegistry, EncodingConfigAt("coreum-mainnet-1", 99).InterfaceRegistry)
+	require.Same(t, v2.InterfaceRegistry, EncodingConfigAt("coreum-mainnet-1", 100).InterfaceRegistry)
+	require.Same(t, v2.InterfaceRegistry, EncodingConfigAt("coreum-mainnet-1", 199).InterfaceRegistry)
+	require.Same(t, EncodingConfig.InterfaceRegistry, EncodingConfigAt("coreum-mainnet-1", 200).InterfaceRegistry)
+	require.Same(t, AppCodec, CodecAt("coreum-mainnet-1", 300))
+	require.Same(t, legacyEncodingConfigs[2].config.InterfaceRegistry, EncodingConfigAt("coreum-testnet-1", 300).InterfaceRegistry)
+}
@@ -0,0 +1,96 @@
+package db
+
+import (
+	pg "github.com/go-pg/pg/v10"
+)
+
+const (
+	// AuditCursor is the index cursor holding the highest height of block table audited.
+	AuditCursor = "audit"
+
+	// RawAuditCursor is the index cursor holding the highest height of raw_block table audited.
+	RawAuditCursor = "raw_audit"
+)
+
+// HeightRange is a closed range of block heights.
+type HeightRange struct {
+	From int64 `pg:"gap_from"`
+	To   int64 `pg:"gap_to"`
+}
+
+// GetBlockHeightGaps returns ranges of heights which have no block between from and to.
+// The height from is expected to be stored, and heights after the last block up to to are a gap.
+func (db *Database) GetBlockHeightGaps(chainInfoID int, from, to int64) ([]HeightRange, error) {
+	gaps := make([]HeightRange, 0)
+	_, err := db.Query(&gaps, "SELECT height + 1 AS gap_from, next_height - 1 AS gap_to FROM ("+
+		"SELECT height, lead(height, 1, CAST(? AS bigint) + 1) OVER (ORDER BY height) AS next_height FROM block WHERE chain_info_id = ? AND height BETWEEN ? AND ?"+
+		") AS t WHERE next_height > height + 1 ORDER BY height", to, chainInfoID, from, to)
+
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return gaps, nil
+		}
+		return gaps, err
+	}
+
+	return gaps, nil
+}
+
+// GetBlockNumTxsMismatches returns heights between from and to whose num_txs differs
+// from the number of transactions stored for the block.
+func (db *Database) GetBlockNumTxsMismatches(chainInfoID int, from, to int64) ([]int64, error) {
+	heights := make([]int64, 0)
+	_, err := db.Query(&heights, "SELECT b.height FROM block AS b "+
+		"LEFT JOIN transaction AS t ON t.chain_info_id = b.chain_info_id AND t.height = b.height "+
+		"WHERE b.chain_info_id = ? AND b.height BETWEEN ? AND ? "+
+		"GROUP BY b.id, b.height, b.num_txs HAVING b.num_txs <> count(t.id) ORDER BY b.height", chainInfoID, from, to)
+
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return heights, nil
+		}
+		return heights, err
+	}
+
+	return heights, nil
+}
+
+// GetEarliestBlockHeight returns the lowest height stored in block table. 0 is returned when no block exists.
+func (db *Database) GetEarliestBlockHeight(chainInfoID int) (int64, error) {
+	var height int64
+	_, err := db.QueryOne(pg.Scan(&height), "SELECT coalesce(min(height), 0) FROM block WHERE chain_info_id = ?", chainInfoID)
+	if err != nil {
+		return 0, err
+	}
+
+	return height, nil
+}
+
+// GetBlockHeightGaps returns ranges of heights which have no raw block between from and to.
+// The height from is expected to be stored, and heights after the last raw block up to to are a gap.
+func (db *RawDatabase) GetBlockHeightGaps(chainID string, from, to int64) ([]HeightRange, error) {
+	gaps := make([]HeightRange, 0)
+	_, err := db.Query(&gaps, "SELECT height + 1 AS gap_from, next_height - 1 AS gap_to FROM ("+
+		"SELECT height, lead(height, 1, CAST(? AS bigint) + 1) OVER (ORDER BY height) AS next_height FROM raw_block WHERE chain_id = ? AND height BETWEEN ? AND ?"+
+		") AS t WHERE next_height > height + 1 ORDER BY height", to, chainID, from, to)
+
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return gaps, nil
+		}
+		return gaps, err
+	}
+
+	return gaps, nil
+}
+
+// GetEarliestBlockHeight returns the lowest height stored in raw_block table. 0 is returned when no block exists.
+func (db *RawDatabase) GetEarliestBlockHeight(chainID string) (int64, error) {
+	var height int64
+	_, err := db.QueryOne(pg.Scan(&height), "SELECT coalesce(min(height), 0) FROM raw_block WHERE chain_id = ?", chainID)
+	if err != nil {
+		return 0, err
+	}
+
+	return height, nil
+}
@@ -0,0 +1,66 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	mdschema "github.com/cosmostation/mintscan-database/schema"
+	pg "github.com/go-pg/pg/v10"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBlockHeightGaps(t *testing.T) {
+	chainInfos, err := db.GetChainInfo()
+	require.NoError(t, err)
+	require.NotEmpty(t, chainInfos)
+	chainInfoID := int(chainInfos[0].ID)
+
+	base := int64(1 << 40)
+	deleteBlocks := func() {
+		_, err := db.Model((*mdschema.Block)(nil)).
+			Where("chain_info_id = ?", chainInfoID).
+			Where("height >= ?", base).
+			Delete()
+		require.NoError(t, err)
+	}
+	deleteBlocks()
+	defer deleteBlocks()
+
+	err = db.RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		for _, h := range []int64{0, 1, 4, 5, 9} {
+			_, err := tx.Model(&mdschema.Block{
+				ChainInfoID: chainInfoID,
+				Height:      base + h,
+				Hash:        fmt.Sprint("GAPTEST", h),
+				ParentHash:  fmt.Sprint("GAPTEST", h-1),
+				NumTxs:      h % 2, // blocks of odd heights miss their transaction
+				Timestamp:   time.Now().UTC(),
+			}).Insert()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	gaps, err := db.GetBlockHeightGaps(chainInfoID, base, base+9)
+	require.NoError(t, err)
+	require.Equal(t, []HeightRange{{From: base + 2, To: base + 3}, {From: base + 6, To: base + 8}}, gaps)
+
+	// a range starting at a stored height finds the gap right after it
+	gaps, err = db.GetBlockHeightGaps(chainInfoID, base+5, base+9)
+	require.NoError(t, err)
+	require.Equal(t, []HeightRange{{From: base + 6, To: base + 8}}, gaps)
+
+	// heights after the last block of the range are a gap, so a range ending in a gap does not hide it
+	gaps, err = db.GetBlockHeightGaps(chainInfoID, base+4, base+7)
+	require.NoError(t, err)
+	require.Equal(t, []HeightRange{{From: base + 6, To: base + 7}}, gaps)
+
+	mismatches, err := db.GetBlockNumTxsMismatches(chainInfoID, base, base+9)
+	require.NoError(t, err)
+	require.Equal(t, []int64{base + 1, base + 5, base + 9}, mismatches)
+}
@@ -0,0 +1,93 @@
+package db
+
+import (
+	"context"
+
+	mdschema "github.com/cosmostation/mintscan-database/schema"
+	pg "github.com/go-pg/pg/v10"
+)
+
+// ReplaceExportedData replaces everything stored at the height of the given block, including the events of the block,
+// in a single transaction. Validator miss ranges are not replaced because they are accumulated over previous heights.
+func (db *Database) ReplaceExportedData(e *ExportedData) error {
+	height := e.Block.Height
+	chainInfoID := e.Block.ChainInfoID
+
+	return db.RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		if err := deleteHeightModels(tx, chainInfoID, "height = ?", height); err != nil {
+			return err
+		}
+		if err := deleteTxEvents(tx, chainInfoID, "height = ?", height); err != nil {
+			return err
+		}
+		if err := deleteTxGas(tx, chainInfoID, "height = ?", height); err != nil {
+			return err
+		}
+		if err := deleteFees(tx, chainInfoID, "height = ?", height); err != nil {
+			return err
+		}
+
+		res, err := tx.Model((*mdschema.Transaction)(nil)).
+			Where("chain_info_id = ?", chainInfoID).
+			Where("height = ?", height).
+			Delete()
+		if err != nil {
+			return err
+		}
+
+		// the transactions of the block are counted again when it is inserted
+		if err := addNumberOfTxs(tx, chainInfoID, -int64(res.RowsAffected())); err != nil {
+			return err
+		}
+
+		_, err = tx.Model((*mdschema.Block)(nil)).
+			Where("chain_info_id = ?", chainInfoID).
+			Where("height = ?", height).
+			Delete()
+		if err != nil {
+			return err
+		}
+
+		if err := db.insertBasicData(tx, e.BasicData); err != nil {
+			return err
+		}
+		return replaceBlockEvents(tx, e)
+	})
+}
+
+// ReplaceExportedData replaces the raw block and raw transactions stored at the height of the given block in a single transaction.
+func (db *RawDatabase) ReplaceExportedData(e *mdschema.RawData) error {
+	e = compressRawData(e)
+	height := e.Block.Height
+	chainID := e.Block.ChainID
+
+	return db.RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		_, err := tx.Model((*mdschema.RawTransaction)(nil)).
+			Where("chain_id = ?", chainID).
+			Where("height = ?", height).
+			Delete()
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Model((*mdschema.RawBlock)(nil)).
+			Where("chain_id = ?", chainID).
+			Where("height = ?", height).
+			Delete()
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Model(e.Block).Insert(); err != nil {
+			return err
+		}
+
+		if len(e.Transactions) > 0 {
+			if _, err := tx.Model(&e.Transactions).Insert(); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,232 @@
+package db
+
+import (
+	"context"
+
+	mdschema "github.com/cosmostation/mintscan-database/schema"
+	pg "github.com/go-pg/pg/v10"
+)
+
+// txHashModels are tables which are not related to chain_info but keep the hash of the transaction they were exported from.
+var txHashModels = []interface{}{
+	(*mdschema.TMA)(nil),
+	(*mdschema.PowerEventHistory)(nil),
+	(*mdschema.Deposit)(nil),
+	(*mdschema.Vote)(nil),
+}
+
+// deleteHeightModels deletes the rows of tables which are not related to chain_info and were exported from the blocks of the chain
+// matched by condition. Rows are matched by the transactions and blocks being deleted, so rows of other chains at the same heights are kept.
+// It must be called before the transactions and blocks are deleted.
+func deleteHeightModels(tx *pg.Tx, chainInfoID int, condition string, height int64) error {
+	hashes := tx.Model((*mdschema.Transaction)(nil)).
+		Column("hash").
+		Where("chain_info_id = ?", chainInfoID).
+		Where(condition, height)
+	for _, model := range txHashModels {
+		_, err := tx.Model(model).
+			Where("tx_hash IN (?)", hashes).
+			Delete()
+		if err != nil {
+			return err
+		}
+	}
+
+	// miss details of a height are exported with the next block, which reports the signatures of the height
+	parents := tx.Model((*mdschema.Block)(nil)).
+		Column("height", "proposer").
+		Where("chain_info_id = ?", chainInfoID).
+		Where(condition, height-1)
+	_, err := tx.Model((*mdschema.MissDetail)(nil)).
+		Where("(height, proposer) IN (?)", parents).
+		Delete()
+	if err != nil {
+		return err
+	}
+
+	// evidence keeps the height of the misbehavior, so it is matched by the time of the block which included it
+	times := tx.Model((*mdschema.Block)(nil)).
+		Column("timestamp").
+		Where("chain_info_id = ?", chainInfoID).
+		Where(condition, height)
+	_, err = tx.Model((*mdschema.Evidence)(nil)).
+		Where("timestamp IN (?)", times).
+		Delete()
+	return err
+}
+
+// trimMisses cuts the miss ranges at the height from, so that the blocks synced again extend ranges ending before it.
+// Misses of a height are exported with the next block like miss details, and a range counts every height it spans.
+// It must be called before the blocks are deleted.
+func trimMisses(tx *pg.Tx, chainInfoID int, from int64) error {
+	_, err := tx.Model((*mdschema.Miss)(nil)).
+		Where("start_height >= ?", from).
+		Delete()
+	if err != nil {
+		return err
+	}
+
+	prevTime := tx.Model((*mdschema.Block)(nil)).
+		Column("timestamp").
+		Where("chain_info_id = ?", chainInfoID).
+		Where("height = ?", from-1)
+	_, err = tx.Model((*mdschema.Miss)(nil)).
+		Set("end_height = ?", from-1).
+		Set("missing_count = ? - start_height", from).
+		Set("end_time = coalesce((?), end_time)", prevTime).
+		Where("end_height >= ?", from).
+		Update()
+	return err
+}
+
+// GetBlockHash returns the hash of the block stored at the given height.
+// An empty string is returned when the block does not exist.
+func (db *Database) GetBlockHash(chainInfoID int, height int64) (string, error) {
+	var hash string
+	err := db.Model(&mdschema.Block{}).
+		Column("hash").
+		Where("chain_info_id = ?", chainInfoID).
+		Where("height = ?", height).
+		Limit(1).
+		Select(&hash)
+
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return hash, nil
+}
+
+// GetChainInfoID returns the id of the chain info of the chain-id. 0 is returned when the chain does not exist.
+func (db *Database) GetChainInfoID(chainID string) (int, error) {
+	var id int
+	err := db.Model((*mdschema.ChainInfo)(nil)).
+		Column("id").
+		Where("chain_id = ?", chainID).
+		Limit(1).
+		Select(&id)
+
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// RawRollbackCursor is the index cursor holding the height from which raw data of the chain is still to be rolled back.
+// It is 0 when no rollback is pending.
+const RawRollbackCursor = "raw_rollback"
+
+// DeleteBlocksFrom deletes blocks at the given height and above together with the data exported from them and their events.
+// The number of transactions of the chain is decreased by the number of deleted transactions.
+// Raw data is kept in another database, so the height is saved in RawRollbackCursor in the same transaction
+// and the raw data is rolled back until the cursor is cleared.
+func (db *Database) DeleteBlocksFrom(chainInfoID int, chainID string, height int64) error {
+	return db.RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		if err := deleteHeightModels(tx, chainInfoID, "height >= ?", height); err != nil {
+			return err
+		}
+		if err := deleteTxEvents(tx, chainInfoID, "height >= ?", height); err != nil {
+			return err
+		}
+		if err := deleteTxGas(tx, chainInfoID, "height >= ?", height); err != nil {
+			return err
+		}
+		if err := deleteFees(tx, chainInfoID, "height >= ?", height); err != nil {
+			return err
+		}
+		if err := trimMisses(tx, chainInfoID, height-1); err != nil {
+			return err
+		}
+		if err := deleteBlockEvents(tx, chainID, "height >= ?", height); err != nil {
+			return err
+		}
+		if err := setIndexCursor(tx, chainInfoID, RawRollbackCursor, height); err != nil {
+			return err
+		}
+		// the heights exported again are audited again
+		for _, name := range []string{AuditCursor, RawAuditCursor} {
+			if err := lowerIndexCursor(tx, chainInfoID, name, height-1); err != nil {
+				return err
+			}
+		}
+
+		res, err := tx.Model((*mdschema.Transaction)(nil)).
+			Where("chain_info_id = ?", chainInfoID).
+			Where("height >= ?", height).
+			Delete()
+		if err != nil {
+			return err
+		}
+
+		if err := addNumberOfTxs(tx, chainInfoID, -int64(res.RowsAffected())); err != nil {
+			return err
+		}
+
+		_, err = tx.Model((*mdschema.Block)(nil)).
+			Where("chain_info_id = ?", chainInfoID).
+			Where("height >= ?", height).
+			Delete()
+
+		return err
+	})
+}
+
+// GetBlockHash returns the hash of the raw block stored at the given height.
+// An empty string is returned when the block does not exist.
+func (db *RawDatabase) GetBlockHash(chainID string, height int64) (string, error) {
+	var hash string
+	err := db.Model(&mdschema.RawBlock{}).
+		Column("block_hash").
+		Where("chain_id = ?", chainID).
+		Where("height = ?", height).
+		Limit(1).
+		Select(&hash)
+
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return hash, nil
+}
+
+// GetChainLatestBlockHeight returns the highest height of the chain stored in raw_block table.
+// 0 is returned when no block exists and -1 when the query fails.
+func (db *RawDatabase) GetChainLatestBlockHeight(chainID string) (int64, error) {
+	var height int64
+	_, err := db.QueryOne(pg.Scan(&height), "SELECT coalesce(max(height), 0) FROM raw_block WHERE chain_id = ?", chainID)
+	if err != nil {
+		return -1, err
+	}
+
+	return height, nil
+}
+
+// DeleteBlocksFrom deletes raw blocks and raw transactions at the given height and above.
+func (db *RawDatabase) DeleteBlocksFrom(chainID string, height int64) error {
+	return db.RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		_, err := tx.Model((*mdschema.RawTransaction)(nil)).
+			Where("chain_id = ?", chainID).
+			Where("height >= ?", height).
+			Delete()
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Model((*mdschema.RawBlock)(nil)).
+			Where("chain_id = ?", chainID).
+			Where("height >= ?", height).
+			Delete()
+
+		return err
+	})
+}
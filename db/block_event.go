@@ -0,0 +1,173 @@
+package db
+
+import (
+	"context"
+
+	pg "github.com/go-pg/pg/v10"
+)
+
+// Phase of a block event, the part of block execution which emitted it.
+const (
+	PHASE_BEGIN_BLOCK = "begin_block"
+	PHASE_END_BLOCK   = "end_block"
+	PHASE_TX          = "tx"
+)
+
+// BlockEvent is an ABCI event emitted while executing a block.
+// TxIndex and TxHash are the index in the block and the hash of the transaction for events of tx phase, -1 and empty otherwise.
+type BlockEvent struct {
+	tableName struct{} `pg:"block_event,alias:block_event"`
+
+	ChainID    string `pg:",pk"`
+	Height     int64  `pg:",pk,use_zero"`
+	Phase      string `pg:",pk"`
+	TxIndex    int    `pg:",pk,use_zero"`
+	EventIndex int    `pg:",pk,use_zero"`
+	Type       string `pg:",notnull"`
+	TxHash     string
+}
+
+// BlockEventAttribute is an attribute of a block event. The type of the event is repeated
+// so that attributes can be searched by event type without a join.
+type BlockEventAttribute struct {
+	tableName struct{} `pg:"block_event_attribute,alias:block_event_attribute"`
+
+	ChainID    string `pg:",pk"`
+	Height     int64  `pg:",pk,use_zero"`
+	Phase      string `pg:",pk"`
+	TxIndex    int    `pg:",pk,use_zero"`
+	EventIndex int    `pg:",pk,use_zero"`
+	Position   int    `pg:",pk,use_zero"`
+	Type       string `pg:",notnull"`
+	Key        string `pg:",notnull,use_zero"`
+	Value      string `pg:",notnull,use_zero"`
+	TxHash     string
+}
+
+// blockEventIndexes are created with the block event tables to search events by type and attribute.
+// Values are indexed by their md5 hash, since a btree entry can not hold an arbitrarily long value
+// and an insert of such a value would fail. Queries match values with md5(value) = md5(?) to use it.
+var blockEventIndexes = []string{
+	"ALTER TABLE block_event ADD COLUMN IF NOT EXISTS tx_hash text",
+	"ALTER TABLE block_event_attribute ADD COLUMN IF NOT EXISTS tx_hash text",
+	"CREATE INDEX IF NOT EXISTS block_event_type_idx ON block_event (chain_id, type, height)",
+	"DROP INDEX IF EXISTS block_event_attribute_key_value_idx",
+	"CREATE INDEX IF NOT EXISTS block_event_attribute_key_value_hash_idx ON block_event_attribute (chain_id, type, key, md5(value), height)",
+}
+
+// replaceBlockEvents replaces the events and attributes stored at the height of the exported block,
+// so that a height exported again is never stored twice.
+func replaceBlockEvents(tx *pg.Tx, e *ExportedData) error {
+	if e.Block == nil || e.Events == nil {
+		return nil
+	}
+	if err := deleteBlockEvents(tx, e.ChainID, "height = ?", e.Block.Height); err != nil {
+		return err
+	}
+
+	if len(e.Events) > 0 {
+		if _, err := tx.Model(&e.Events).Insert(); err != nil {
+			return err
+		}
+	}
+
+	if len(e.Attributes) > 0 {
+		if _, err := tx.Model(&e.Attributes).Insert(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func deleteBlockEvents(tx *pg.Tx, chainID, condition string, height int64) error {
+	for _, model := range []interface{}{(*BlockEventAttribute)(nil), (*BlockEvent)(nil)} {
+		_, err := tx.Model(model).
+			Where("chain_id = ?", chainID).
+			Where(condition, height).
+			Delete()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetBlockEvents returns the events of the chains of the type from the height from to the height to in descending order of height.
+// Heights are not bounded by from or to when it is 0, and events are not limited when limit is 0.
+// When key is not empty, only events having an attribute of the key are returned, and of the value when value is not empty too.
+func (db *Database) GetBlockEvents(chainIDs []string, eventType, key, value string, from, to int64, limit int) ([]BlockEvent, error) {
+	events := make([]BlockEvent, 0)
+	q := db.Model(&events).
+		Where("block_event.chain_id IN (?)", pg.In(chainIDs)).
+		Where("block_event.type = ?", eventType)
+	if from > 0 {
+		q = q.Where("block_event.height >= ?", from)
+	}
+	if to > 0 {
+		q = q.Where("block_event.height <= ?", to)
+	}
+	if key != "" {
+		sub := db.Model((*BlockEventAttribute)(nil)).
+			ColumnExpr("1").
+			Where("block_event_attribute.chain_id = block_event.chain_id").
+			Where("block_event_attribute.height = block_event.height").
+			Where("block_event_attribute.phase = block_event.phase").
+			Where("block_event_attribute.tx_index = block_event.tx_index").
+			Where("block_event_attribute.event_index = block_event.event_index").
+			Where("block_event_attribute.type = ?", eventType).
+			Where("block_event_attribute.key = ?", key)
+		if value != "" {
+			sub = sub.Where("md5(block_event_attribute.value) = md5(?)", value)
+		}
+		q = q.Where("EXISTS (?)", sub)
+	}
+
+	err := q.
+		Order("block_event.height DESC", "block_event.phase ASC", "block_event.tx_index ASC", "block_event.event_index ASC").
+		Limit(limit).
+		Select()
+
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return events, nil
+		}
+		return events, err
+	}
+
+	return events, nil
+}
+
+// GetBlockEventAttributes returns the attributes of the events in the order they were emitted.
+func (db *Database) GetBlockEventAttributes(events []BlockEvent) ([]BlockEventAttribute, error) {
+	attrs := make([]BlockEventAttribute, 0)
+	if len(events) == 0 {
+		return attrs, nil
+	}
+
+	err := db.Model(&attrs).
+		WhereGroup(func(q *pg.Query) (*pg.Query, error) {
+			for _, e := range events {
+				q = q.WhereOrGroup(func(q *pg.Query) (*pg.Query, error) {
+					q = q.Where("chain_id = ?", e.ChainID).
+						Where("height = ?", e.Height).
+						Where("phase = ?", e.Phase).
+						Where("tx_index = ?", e.TxIndex).
+						Where("event_index = ?", e.EventIndex)
+					return q, nil
+				})
+			}
+			return q, nil
+		}).
+		Order("height DESC", "phase ASC", "tx_index ASC", "event_index ASC", "position ASC").
+		Select()
+
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return attrs, nil
+		}
+		return attrs, err
+	}
+
+	return attrs, nil
+}
@@ -0,0 +1,69 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mdschema "github.com/cosmostation/mintscan-database/schema"
+	pg "github.com/go-pg/pg/v10"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteBlocksFromTrimsMisses(t *testing.T) {
+	chainInfos, err := db.GetChainInfo()
+	require.NoError(t, err)
+	require.NotEmpty(t, chainInfos)
+	chainInfo := chainInfos[0]
+	chainInfoID := int(chainInfo.ID)
+
+	address := "MISSTEST"
+	height := int64(1 << 40)
+	miss := func(h int64) error {
+		return db.RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+			return insertMisses(tx, []mdschema.Miss{{
+				Address:      address,
+				StartHeight:  h,
+				EndHeight:    h,
+				MissingCount: 1,
+				StartTime:    time.Now().UTC(),
+				EndTime:      time.Now().UTC(),
+			}})
+		})
+	}
+	misses := func() []mdschema.Miss {
+		ms := make([]mdschema.Miss, 0)
+		require.NoError(t, db.Model(&ms).Where("address = ?", address).Order("start_height ASC").Select())
+		return ms
+	}
+	defer func() {
+		_, err := db.Model((*mdschema.Miss)(nil)).Where("address = ?", address).Delete()
+		require.NoError(t, err)
+		require.NoError(t, db.SetIndexCursor(chainInfoID, RawRollbackCursor, 0))
+	}()
+
+	for h := height; h < height+5; h++ {
+		require.NoError(t, miss(h))
+	}
+	require.NoError(t, miss(height+7))
+
+	// the block at height+3 reported the miss at height+2, so the range ends at height+1
+	require.NoError(t, db.DeleteBlocksFrom(chainInfoID, chainInfo.ChainID, height+3))
+	ms := misses()
+	require.Len(t, ms, 1)
+	require.Equal(t, height, ms[0].StartHeight)
+	require.Equal(t, height+1, ms[0].EndHeight)
+	require.Equal(t, int64(2), ms[0].MissingCount)
+
+	// the raw data of the chain is to be rolled back from the same height
+	pending, err := db.GetIndexCursor(chainInfoID, RawRollbackCursor)
+	require.NoError(t, err)
+	require.Equal(t, height+3, pending)
+
+	// syncing the heights again extends the trimmed range without counting a height twice
+	require.NoError(t, miss(height+2))
+	ms = misses()
+	require.Len(t, ms, 1)
+	require.Equal(t, height+2, ms[0].EndHeight)
+	require.Equal(t, int64(3), ms[0].MissingCount)
+}
@@ -0,0 +1,83 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/klauspost/compress/zstd"
+	"go.uber.org/zap"
+
+	mdschema "github.com/cosmostation/mintscan-database/schema"
+)
+
+// chunkZstdV1 marks a chunk compressed with zstd. JSON never begins with this byte,
+// so chunks stored before compression are read as they are.
+const chunkZstdV1 = byte(0x01)
+
+var (
+	// compressChunks enables compression of chunks written to the databases.
+	compressChunks = false
+
+	zstdEncoder, _ = zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedDefault))
+	zstdDecoder, _ = zstd.NewReader(nil)
+)
+
+// SetChunkCompression sets whether chunks are compressed when they are written.
+// Chunk columns must be bytea, which MigrateChunks ensures, before it is enabled.
+func SetChunkCompression(enabled bool) {
+	compressChunks = enabled
+	zap.S().Debugf("CompressChunks : %t\n", compressChunks)
+}
+
+// CompressChunk returns the chunk compressed with a version marker. A compressed chunk is returned as it is.
+func CompressChunk(chunk []byte) []byte {
+	if len(chunk) == 0 || IsCompressedChunk(chunk) {
+		return chunk
+	}
+	return zstdEncoder.EncodeAll(chunk, []byte{chunkZstdV1})
+}
+
+// DecompressChunk returns the JSON of the chunk. Chunks without a version marker are returned as they are.
+func DecompressChunk(chunk []byte) ([]byte, error) {
+	if !IsCompressedChunk(chunk) {
+		return chunk, nil
+	}
+	json, err := zstdDecoder.DecodeAll(chunk[1:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress chunk: %s", err)
+	}
+	return json, nil
+}
+
+// IsCompressedChunk reports whether the chunk begins with a version marker of compression.
+func IsCompressedChunk(chunk []byte) bool {
+	return len(chunk) > 0 && chunk[0] == chunkZstdV1
+}
+
+// compressTransactions returns a copy of txs with compressed chunks when compression is enabled.
+func compressTransactions(txs []mdschema.Transaction) []mdschema.Transaction {
+	if !compressChunks || len(txs) == 0 {
+		return txs
+	}
+	compressed := make([]mdschema.Transaction, len(txs))
+	for i := range txs {
+		compressed[i] = txs[i]
+		compressed[i].Chunk = CompressChunk(txs[i].Chunk)
+	}
+	return compressed
+}
+
+// compressRawData returns a copy of the raw data with compressed chunks when compression is enabled.
+func compressRawData(e *mdschema.RawData) *mdschema.RawData {
+	if !compressChunks {
+		return e
+	}
+	block := *e.Block
+	block.Chunk = CompressChunk(block.Chunk)
+
+	txs := make([]mdschema.RawTransaction, len(e.Transactions))
+	for i := range e.Transactions {
+		txs[i] = e.Transactions[i]
+		txs[i].Chunk = CompressChunk(e.Transactions[i].Chunk)
+	}
+	return &mdschema.RawData{Block: &block, Transactions: txs}
+}
@@ -0,0 +1,45 @@
+package db
+
+import (
+	"testing"
+
+	mdschema "github.com/cosmostation/mintscan-database/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressChunk(t *testing.T) {
+	json := []byte(`{"height":"1","txhash":"ABCD","tx":{"body":{"messages":[]}}}`)
+
+	compressed := CompressChunk(json)
+	require.True(t, IsCompressedChunk(compressed))
+	require.Equal(t, compressed, CompressChunk(compressed))
+
+	decompressed, err := DecompressChunk(compressed)
+	require.NoError(t, err)
+	require.Equal(t, json, decompressed)
+
+	// chunks stored before compression are read as they are
+	decompressed, err = DecompressChunk(json)
+	require.NoError(t, err)
+	require.Equal(t, json, decompressed)
+
+	_, err = DecompressChunk([]byte{chunkZstdV1, 0xff})
+	require.Error(t, err)
+}
+
+func TestCompressRawData(t *testing.T) {
+	defer SetChunkCompression(false)
+
+	raw := &mdschema.RawData{
+		Block:        &mdschema.RawBlock{Chunk: []byte(`{"block":{}}`)},
+		Transactions: []mdschema.RawTransaction{{Chunk: []byte(`{"tx":{}}`)}},
+	}
+	require.Same(t, raw, compressRawData(raw))
+
+	SetChunkCompression(true)
+	compressed := compressRawData(raw)
+	require.True(t, IsCompressedChunk(compressed.Block.Chunk))
+	require.True(t, IsCompressedChunk(compressed.Transactions[0].Chunk))
+	require.False(t, IsCompressedChunk(raw.Block.Chunk))
+	require.False(t, IsCompressedChunk(raw.Transactions[0].Chunk))
+}
@@ -34,12 +34,184 @@ func Connect(dbcfg *mblconfig.DatabaseConfig) *Database {
 	return &Database{db}
 }
 
+// ConnectChain opens database connections to the schemas of another chain indexed by the same process.
+// The schemas are bound to the connections of a pool when it is opened, and the models of mintscan-database
+// can not take them per query, so every chain needs a pool of its own. The schemas of the package set by Connect
+// are restored, so that the pool of another chain does not change those of base.
+func ConnectChain(dbcfg *mblconfig.DatabaseConfig) *Database {
+	commonSchema, chainSchema := mdschema.GetCommonSchema(), mdschema.GetChainSchema()
+	defer func() {
+		mdschema.SetCommonSchema(commonSchema)
+		mdschema.SetChainSchema(chainSchema)
+	}()
+
+	db := mddb.Connect(dbcfg.Host, dbcfg.Port, dbcfg.User, dbcfg.Password, dbcfg.DBName, dbcfg.CommonSchema, dbcfg.ChainSchema, dbcfg.Timeout)
+	return &Database{db}
+}
+
 // CreateTables creates database tables using ORM (Object Relational Mapper).
 func (db *Database) CreateTablesAndIndexes() {
 	// 생성 오류 시 패닉
 	db.CreateTables()
 }
 
+// ExportedData is the data exported for a height together with the rows of this repository
+// which are stored in the same transaction as the block.
+type ExportedData struct {
+	*schema.BasicData
+
+	// ChainID is the chain-id of the block, which keys the events of the block.
+	ChainID string `json:"chain_id"`
+
+	// Outbox is the notifications of the block, which are delivered once the block is committed.
+	Outbox []OutboxEntry `json:"outbox,omitempty"`
+
+	// Events and Attributes are the events of the block results. They replace the events stored at the height,
+	// which are kept when Events is nil.
+	Events     []BlockEvent          `json:"events,omitempty"`
+	Attributes []BlockEventAttribute `json:"attributes,omitempty"`
+}
+
+// InsertExportedData inserts the data exported for a height in a single transaction, so that
+// notifications and events of the block are never saved without the block. Chunks of transactions
+// are compressed when compression is enabled.
+func (db *Database) InsertExportedData(e *ExportedData) error {
+	return db.RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		if err := db.insertBasicData(tx, e.BasicData); err != nil {
+			return err
+		}
+		if err := replaceBlockEvents(tx, e); err != nil {
+			return err
+		}
+
+		if len(e.GenesisValidatorsSet) > 0 {
+			if _, err := tx.Model(&e.GenesisValidatorsSet).Insert(); err != nil {
+				return err
+			}
+		}
+
+		if len(e.Proposals) > 0 {
+			if _, err := tx.Model(&e.Proposals).OnConflict("(id) DO UPDATE").Insert(); err != nil {
+				return err
+			}
+		}
+
+		if err := insertMisses(tx, e.MissBlocks); err != nil {
+			return err
+		}
+
+		return insertOutboxEntries(tx, e.Outbox)
+	})
+}
+
+// insertBasicData inserts the block, transactions and the rows of the height which are exported from them,
+// and counts the transactions of the block in the number of transactions of the chain.
+// It follows InsertExportedData of mintscan-database, which can not join the transaction of the block,
+// and is the only place basic data is inserted in this repository.
+func (db *Database) insertBasicData(tx *pg.Tx, e *schema.BasicData) error {
+	if e.Block != nil {
+		if err := db.InsertBlock(tx, e.Block); err != nil {
+			return err
+		}
+
+		// the number of txs is counted in database, so the exporter does not read it before writing
+		if err := addNumberOfTxs(tx, e.Block.ChainInfoID, int64(e.Block.NumTxs)); err != nil {
+			return err
+		}
+	}
+
+	if len(e.Transactions) > 0 {
+		for i := range e.Transactions {
+			if e.Block == nil || e.Block.ID == 0 {
+				return fmt.Errorf("failed to insert result txs, can not get block.id")
+			}
+			e.Transactions[i].BlockID = e.Block.ID
+		}
+		err := db.InsertTransaction(tx, compressTransactions(e.Transactions), e.TMAs)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(e.Evidence) > 0 {
+		if _, err := tx.Model(&e.Evidence).Insert(); err != nil {
+			return err
+		}
+	}
+
+	if len(e.MissDetailBlocks) > 0 {
+		if _, err := tx.Model(&e.MissDetailBlocks).Insert(); err != nil {
+			return err
+		}
+	}
+
+	if len(e.ValidatorsPowerEventHistory) > 0 {
+		if _, err := tx.Model(&e.ValidatorsPowerEventHistory).Insert(); err != nil {
+			return err
+		}
+	}
+
+	if len(e.Deposits) > 0 {
+		if _, err := tx.Model(&e.Deposits).Insert(); err != nil {
+			return err
+		}
+	}
+
+	if len(e.Votes) > 0 {
+		if _, err := tx.Model(&e.Votes).OnConflict("DO NOTHING").Insert(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addNumberOfTxs adds delta to the number of transactions of the chain in the transaction.
+func addNumberOfTxs(tx *pg.Tx, chainInfoID int, delta int64) error {
+	if delta == 0 {
+		return nil
+	}
+	_, err := tx.Model((*schema.ChainInfo)(nil)).
+		Set("number_of_txs = number_of_txs + ?", delta).
+		Where("id = ?", chainInfoID).
+		Update()
+	return err
+}
+
+// insertMisses extends the miss range of a validator ending at the previous height with each missed height,
+// and starts a new range when the validator did not miss the previous height.
+func insertMisses(tx *pg.Tx, miss []schema.Miss) error {
+	for i := range miss {
+		m := &miss[i]
+		res, err := tx.Model((*schema.Miss)(nil)).
+			Set("end_height = ?", m.EndHeight).
+			Set("missing_count = missing_count + ?", m.MissingCount).
+			Set("end_time = ?", m.EndTime).
+			Where("address = ?", m.Address).
+			Where("end_height = ?", m.StartHeight-1).
+			Update()
+		if err != nil {
+			return err
+		}
+		if res.RowsAffected() > 0 {
+			continue
+		}
+
+		if _, err := tx.Model(m).Insert(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// InsertRefineData inserts the data refined from the raw database. Chunks of transactions are compressed when compression is enabled.
+func (db *Database) InsertRefineData(e *schema.RefineData) error {
+	c := *e
+	c.Transactions = compressTransactions(e.Transactions)
+	return db.Database.InsertRefineData(&c)
+}
+
 func (db *Database) InsertRefineRealTimeData(e *schema.BasicData) error {
 	err := db.RunInTransaction(context.Background(), func(tx *pg.Tx) error {
 		err := db.InsertBlock(tx, e.Block)
@@ -55,7 +227,7 @@ func (db *Database) InsertRefineRealTimeData(e *schema.BasicData) error {
 					return fmt.Errorf("failed to insert result txs, can not get block.id")
 				}
 			}
-			err := db.InsertTransaction(tx, e.Transactions, e.TMAs)
+			err := db.InsertTransaction(tx, compressTransactions(e.Transactions), e.TMAs)
 			if err != nil {
 				return err
 			}
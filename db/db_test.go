@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	//mbl
 	"github.com/cosmostation/cosmostation-coreum/custom"
@@ -47,7 +48,7 @@ func TestUpdate_Validator(t *testing.T) {
 		Rank:    5,
 	}
 
-	validator, err := db.GetValidatorByAnyAddr(val.Address)
+	validator, err := db.GetValidatorByAnyAddr(custom.CurrentNetwork.AddressPrefix, val.Address)
 	require.NoError(t, err)
 
 	result, err := db.Model(&validator).
@@ -182,3 +183,43 @@ func TestGetLiveProposal(t *testing.T) {
 
 	t.Log(ps)
 }
+
+func TestConnectChainKeepsSchemasApart(t *testing.T) {
+	cfg := mblconfig.ParseConfig("chain-exporter")
+	chainCfg := cfg.DB
+	chainCfg.ChainSchema = "schema_apart_test"
+
+	_, err := db.Exec("CREATE SCHEMA IF NOT EXISTS ?", pg.Ident(chainCfg.ChainSchema))
+	require.NoError(t, err)
+	defer func() {
+		_, err := db.Exec("DROP SCHEMA IF EXISTS ? CASCADE", pg.Ident(chainCfg.ChainSchema))
+		require.NoError(t, err)
+	}()
+
+	commonSchema, chainSchema := mdschema.GetCommonSchema(), mdschema.GetChainSchema()
+	chain := ConnectChain(&chainCfg)
+	defer chain.Close()
+	require.Equal(t, commonSchema, mdschema.GetCommonSchema())
+	require.Equal(t, chainSchema, mdschema.GetChainSchema())
+	chain.CreateTablesAndIndexes()
+
+	// misses are not keyed by chain, so a miss of one chain must not be seen by another
+	address := "SCHEMAAPARTTEST"
+	_, err = chain.Model(&mdschema.Miss{
+		Address:      address,
+		StartHeight:  1,
+		EndHeight:    1,
+		MissingCount: 1,
+		StartTime:    time.Now().UTC(),
+		EndTime:      time.Now().UTC(),
+	}).Insert()
+	require.NoError(t, err)
+
+	n, err := chain.Model((*mdschema.Miss)(nil)).Where("address = ?", address).Count()
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	n, err = db.Model((*mdschema.Miss)(nil)).Where("address = ?", address).Count()
+	require.NoError(t, err)
+	require.Zero(t, n)
+}
@@ -0,0 +1,51 @@
+package db
+
+import (
+	"time"
+
+	pg "github.com/go-pg/pg/v10"
+)
+
+// EventCursor is the last height whose block event was accepted by the broker for a subject.
+type EventCursor struct {
+	tableName struct{} `pg:"event_cursor"`
+
+	ChainID   string    `pg:",pk"`
+	Subject   string    `pg:",pk"`
+	Height    int64     `pg:",notnull,use_zero"`
+	Timestamp time.Time `pg:"default:now()"`
+}
+
+// GetEventCursor returns the last published height of the subject, 0 when nothing has been published yet.
+func (db *Database) GetEventCursor(chainID, subject string) (int64, error) {
+	var cursor EventCursor
+	err := db.Model(&cursor).
+		Where("chain_id = ?", chainID).
+		Where("subject = ?", subject).
+		Select()
+
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	return cursor.Height, nil
+}
+
+// SetEventCursor saves the last published height of the subject.
+func (db *Database) SetEventCursor(chainID, subject string, height int64) error {
+	cursor := &EventCursor{
+		ChainID:   chainID,
+		Subject:   subject,
+		Height:    height,
+		Timestamp: time.Now(),
+	}
+	_, err := db.Model(cursor).
+		OnConflict("(chain_id, subject) DO UPDATE").
+		Set("height = EXCLUDED.height").
+		Set("timestamp = EXCLUDED.timestamp").
+		Insert()
+	return err
+}
@@ -0,0 +1,240 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	pg "github.com/go-pg/pg/v10"
+
+	mdschema "github.com/cosmostation/mintscan-database/schema"
+)
+
+// DailyFeeCursor is the name of the index cursor of the daily fee rollup.
+const DailyFeeCursor = "daily_fee"
+
+// TxFeePayer is the account which paid the fee of a transaction and the account which granted it, if any.
+type TxFeePayer struct {
+	tableName struct{} `pg:"tx_fee_payer"`
+
+	TxID      int64  `pg:",pk,use_zero"`
+	Payer     string `pg:",notnull"`
+	Granter   string
+	Timestamp time.Time `pg:",notnull"`
+}
+
+// SaveTxFees saves the fees and fee payers and moves the cursor of the fee maker to pointer in a single transaction.
+// Fees and payers of transactions saved before are ignored.
+func (db *Database) SaveTxFees(chainInfoID int, name string, pointer int64, payers []TxFeePayer, fees []mdschema.Fee) error {
+	return db.RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		if len(payers) > 0 {
+			_, err := tx.Model(&payers).
+				OnConflict("DO NOTHING").
+				Insert()
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(fees) > 0 {
+			_, err := tx.Model(&fees).
+				OnConflict("DO NOTHING").
+				Insert()
+			if err != nil {
+				return err
+			}
+		}
+
+		return setIndexCursor(tx, chainInfoID, name, pointer)
+	})
+}
+
+// GetFeesInRange returns the fees of transactions whose id is greater than after and less than or equal to upTo.
+func (db *Database) GetFeesInRange(after, upTo int64) ([]mdschema.Fee, error) {
+	fees := make([]mdschema.Fee, 0)
+	err := db.Model(&fees).
+		Where("tx_id > ?", after).
+		Where("tx_id <= ?", upTo).
+		Order("tx_id ASC").
+		Select()
+
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return fees, nil
+		}
+		return fees, err
+	}
+
+	return fees, nil
+}
+
+// GetDailyFeesAt returns the daily fees of the days.
+func (db *Database) GetDailyFeesAt(days []time.Time) ([]mdschema.DailyFee, error) {
+	dfs := make([]mdschema.DailyFee, 0)
+	if len(days) == 0 {
+		return dfs, nil
+	}
+
+	err := db.Model(&dfs).
+		Where("timestamp IN (?)", pg.In(days)).
+		Select()
+
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return dfs, nil
+		}
+		return dfs, err
+	}
+
+	return dfs, nil
+}
+
+// GetDailyFees returns the daily fees of the days in [from, to) in ascending order of day.
+// Only fees of the denom are returned when denom is not empty.
+func (db *Database) GetDailyFees(from, to time.Time, denom string) ([]mdschema.DailyFee, error) {
+	dfs := make([]mdschema.DailyFee, 0)
+	q := db.Model(&dfs).
+		Where("timestamp >= ?", from).
+		Where("timestamp < ?", to)
+	if denom != "" {
+		q = q.Where("denom = ?", denom)
+	}
+
+	err := q.
+		Order("timestamp ASC", "denom ASC").
+		Select()
+
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return dfs, nil
+		}
+		return dfs, err
+	}
+
+	return dfs, nil
+}
+
+// ReplaceDailyFees replaces the daily fees of the days with the merged ones and moves the cursor
+// of the rollup to pointer in a single transaction, so that no fee is rolled up twice.
+func (db *Database) ReplaceDailyFees(chainInfoID int, name string, pointer int64, days []time.Time, dfs []mdschema.DailyFee) error {
+	return db.RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		if len(days) > 0 {
+			_, err := tx.Model((*mdschema.DailyFee)(nil)).
+				Where("timestamp IN (?)", pg.In(days)).
+				Delete()
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(dfs) > 0 {
+			if _, err := tx.Model(&dfs).Insert(); err != nil {
+				return err
+			}
+		}
+
+		return setIndexCursor(tx, chainInfoID, name, pointer)
+	})
+}
+
+// deleteFees deletes the fees and fee payers of the transactions at the height and above in the transaction.
+// Fees already rolled up are subtracted from their daily fees, so the transactions exported again are counted once.
+// It is called before the transactions are deleted, since they are matched by their ids.
+func deleteFees(tx *pg.Tx, chainInfoID int, condition string, height int64) error {
+	txIDs := tx.Model((*mdschema.Transaction)(nil)).
+		Column("id").
+		Where("chain_info_id = ?", chainInfoID).
+		Where(condition, height)
+
+	var cursor IndexCursor
+	err := tx.Model(&cursor).
+		Where("chain_info_id = ?", chainInfoID).
+		Where("name = ?", DailyFeeCursor).
+		Select()
+	if err != nil && err != pg.ErrNoRows {
+		return err
+	}
+
+	if cursor.Pointer > 0 {
+		fees := make([]mdschema.Fee, 0)
+		err := tx.Model(&fees).
+			Where("tx_id IN (?)", txIDs).
+			Where("tx_id <= ?", cursor.Pointer).
+			Select()
+		if err != nil && err != pg.ErrNoRows {
+			return err
+		}
+		if err := subtractDailyFees(tx, fees); err != nil {
+			return err
+		}
+	}
+
+	for _, model := range []interface{}{(*mdschema.Fee)(nil), (*TxFeePayer)(nil)} {
+		_, err := tx.Model(model).
+			Where("tx_id IN (?)", txIDs).
+			Delete()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dailyFeeKey identifies a daily fee by its day and denom.
+type dailyFeeKey struct {
+	day   int64
+	denom string
+}
+
+// subtractDailyFees subtracts the fees from the daily fees of their days in the transaction.
+func subtractDailyFees(tx *pg.Tx, fees []mdschema.Fee) error {
+	sums := make(map[dailyFeeKey]*big.Int)
+	for i := range fees {
+		amount, ok := new(big.Int).SetString(fees[i].Amount, 10)
+		if !ok {
+			return fmt.Errorf("failed to parse fee amount %s of tx_id %d", fees[i].Amount, fees[i].TxID)
+		}
+		k := dailyFeeKey{fees[i].Timestamp.UTC().Truncate(time.Hour * 24).Unix(), fees[i].Denom}
+		if sums[k] == nil {
+			sums[k] = new(big.Int)
+		}
+		sums[k].Add(sums[k], amount)
+	}
+
+	for k, sum := range sums {
+		day := time.Unix(k.day, 0).UTC()
+
+		var df mdschema.DailyFee
+		err := tx.Model(&df).
+			Where("timestamp = ?", day).
+			Where("denom = ?", k.denom).
+			For("UPDATE").
+			Select()
+		if err != nil {
+			if err == pg.ErrNoRows {
+				continue
+			}
+			return err
+		}
+
+		amount, ok := new(big.Int).SetString(df.Amount, 10)
+		if !ok {
+			return fmt.Errorf("failed to parse daily fee amount %s of %s", df.Amount, df.Denom)
+		}
+		amount.Sub(amount, sum)
+		if amount.Sign() < 0 {
+			amount.SetInt64(0)
+		}
+
+		_, err = tx.Model((*mdschema.DailyFee)(nil)).
+			Set("amount = ?", amount.String()).
+			Where("timestamp = ?", day).
+			Where("denom = ?", k.denom).
+			Update()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	pg "github.com/go-pg/pg/v10"
+)
+
+// Period of a gas stat bucket.
+const (
+	PERIOD_HOUR = "hour"
+	PERIOD_DAY  = "day"
+)
+
+// TxGas is the gas of a successful transaction whose messages are all of a message type.
+// Fee is the amount of the bond denom paid, NULL when the fee is paid in another denom.
+type TxGas struct {
+	tableName struct{} `pg:"tx_gas,alias:tx_gas"`
+
+	ChainInfoID int       `pg:",pk,use_zero"`
+	TxID        int64     `pg:",pk,use_zero"`
+	MsgType     string    `pg:",pk"`
+	Height      int64     `pg:",notnull,use_zero"`
+	GasWanted   int64     `pg:",notnull,use_zero"`
+	GasUsed     int64     `pg:",notnull,use_zero"`
+	Fee         string    `pg:"type:numeric"`
+	Timestamp   time.Time `pg:",notnull"`
+}
+
+// GasStat is the distribution of gas used by transactions of a message type in an hour or a day starting at BucketStart.
+// FeeAvg is NULL when no transaction of the bucket paid the fee in the bond denom.
+type GasStat struct {
+	tableName struct{} `pg:"gas_stat,alias:gas_stat"`
+
+	ChainInfoID  int       `pg:",pk,use_zero"`
+	Period       string    `pg:",pk"`
+	BucketStart  time.Time `pg:",pk"`
+	MsgType      string    `pg:",pk"`
+	NumTxs       int64     `pg:",notnull,use_zero"`
+	GasUsedP50   int64     `pg:",notnull,use_zero"`
+	GasUsedP95   int64     `pg:",notnull,use_zero"`
+	GasUsedAvg   int64     `pg:",notnull,use_zero"`
+	GasWantedAvg int64     `pg:",notnull,use_zero"`
+	FeeAvg       string    `pg:"type:numeric"`
+}
+
+// GasSummary is the distribution of gas used by transactions of a message type since a time.
+type GasSummary struct {
+	MsgType      string
+	NumTxs       int64
+	GasUsedP50   int64
+	GasUsedP95   int64
+	GasUsedAvg   int64
+	GasWantedAvg int64
+	FeeAvg       string
+}
+
+// gasIndexes are created with the gas tables to aggregate samples by time and id.
+// Fees of samples are nullable since fees paid in other denoms are not averaged.
+var gasIndexes = []string{
+	"ALTER TABLE tx_gas ALTER COLUMN fee DROP NOT NULL",
+	"ALTER TABLE gas_stat ALTER COLUMN fee_avg DROP NOT NULL",
+	"CREATE INDEX IF NOT EXISTS tx_gas_timestamp_idx ON tx_gas (chain_info_id, timestamp)",
+	"CREATE INDEX IF NOT EXISTS tx_gas_tx_id_idx ON tx_gas (chain_info_id, tx_id)",
+}
+
+// gasDistribution is the select list aggregating tx_gas rows into a distribution.
+const gasDistribution = `count(*) AS num_txs,
+	percentile_disc(0.5) WITHIN GROUP (ORDER BY gas_used) AS gas_used_p50,
+	percentile_disc(0.95) WITHIN GROUP (ORDER BY gas_used) AS gas_used_p95,
+	round(avg(gas_used))::bigint AS gas_used_avg,
+	round(avg(gas_wanted))::bigint AS gas_wanted_avg,
+	round(avg(fee)) AS fee_avg`
+
+// BucketStart returns the start of the bucket of the period which t belongs to, in UTC.
+func BucketStart(period string, t time.Time) time.Time {
+	if period == PERIOD_HOUR {
+		return t.UTC().Truncate(time.Hour)
+	}
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+// bucketEnd returns the end of the bucket of the period starting at start.
+func bucketEnd(period string, start time.Time) time.Time {
+	if period == PERIOD_HOUR {
+		return start.Add(time.Hour)
+	}
+	return start.Add(24 * time.Hour)
+}
+
+// SaveTxGas saves the samples and moves the cursor of the sampling to pointer in a single transaction.
+// Samples saved again are ignored. Stats are recomputed by RefreshGasStats.
+func (db *Database) SaveTxGas(chainInfoID int, name string, pointer int64, samples []TxGas) error {
+	return db.RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		if len(samples) > 0 {
+			_, err := tx.Model(&samples).
+				OnConflict("DO NOTHING").
+				Insert()
+			if err != nil {
+				return err
+			}
+		}
+
+		return setIndexCursor(tx, chainInfoID, name, pointer)
+	})
+}
+
+// RefreshGasStats recomputes the hourly and daily gas stats of the buckets of the samples whose tx_id is greater than after
+// and less than or equal to upTo, and moves the cursor of the refresh to upTo in a single transaction.
+// Each bucket is recomputed once however many samples of the range it has.
+func (db *Database) RefreshGasStats(chainInfoID int, name string, after, upTo int64) error {
+	return db.RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		hours, err := gasStatHours(tx, chainInfoID, "tx_id > ? AND tx_id <= ?", after, upTo)
+		if err != nil {
+			return err
+		}
+		if err := refreshGasBuckets(tx, chainInfoID, hours); err != nil {
+			return err
+		}
+
+		return setIndexCursor(tx, chainInfoID, name, upTo)
+	})
+}
+
+// gasStatHours returns the hourly buckets of the samples of the chain matching the condition.
+func gasStatHours(tx *pg.Tx, chainInfoID int, condition string, params ...interface{}) ([]time.Time, error) {
+	hours := make([]time.Time, 0)
+	_, err := tx.Query(pg.Scan(&hours), `SELECT DISTINCT date_trunc('hour', timestamp AT TIME ZONE 'UTC')
+FROM tx_gas
+WHERE chain_info_id = ? AND `+condition, append([]interface{}{chainInfoID}, params...)...)
+	return hours, err
+}
+
+// refreshGasBuckets recomputes the hourly buckets and the daily buckets which contain them.
+func refreshGasBuckets(tx *pg.Tx, chainInfoID int, hours []time.Time) error {
+	for _, period := range []string{PERIOD_HOUR, PERIOD_DAY} {
+		buckets := make(map[time.Time]struct{})
+		for _, h := range hours {
+			buckets[BucketStart(period, h)] = struct{}{}
+		}
+		for start := range buckets {
+			if err := refreshGasStats(tx, chainInfoID, period, start); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// refreshGasStats replaces the gas stats of the bucket with the distribution of the samples in the bucket.
+// Stats of the bucket are deleted first, so message types without samples left in the bucket are removed.
+func refreshGasStats(tx *pg.Tx, chainInfoID int, period string, start time.Time) error {
+	_, err := tx.Model((*GasStat)(nil)).
+		Where("chain_info_id = ?", chainInfoID).
+		Where("period = ?", period).
+		Where("bucket_start = ?", start).
+		Delete()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`INSERT INTO gas_stat (chain_info_id, period, bucket_start, msg_type, num_txs, gas_used_p50, gas_used_p95, gas_used_avg, gas_wanted_avg, fee_avg)
+SELECT ?, ?, ?, msg_type, `+gasDistribution+`
+FROM tx_gas
+WHERE chain_info_id = ? AND timestamp >= ? AND timestamp < ?
+GROUP BY msg_type`,
+		chainInfoID, period, start, chainInfoID, start, bucketEnd(period, start))
+	return err
+}
+
+// deleteTxGas deletes the samples of transactions at the height and above in the transaction
+// and recomputes the stats of their buckets from the samples left.
+func deleteTxGas(tx *pg.Tx, chainInfoID int, condition string, height int64) error {
+	hours, err := gasStatHours(tx, chainInfoID, condition, height)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Model((*TxGas)(nil)).
+		Where("chain_info_id = ?", chainInfoID).
+		Where(condition, height).
+		Delete()
+	if err != nil {
+		return err
+	}
+
+	return refreshGasBuckets(tx, chainInfoID, hours)
+}
+
+// GetGasStats returns the gas stats of the chains and the period whose bucket starts in [from, to) in ascending order of bucket.
+// Only stats of the message type are returned when msgType is not empty.
+func (db *Database) GetGasStats(chainInfoIDs []int, period, msgType string, from, to time.Time) ([]GasStat, error) {
+	stats := make([]GasStat, 0)
+	q := db.Model(&stats).
+		Where("chain_info_id IN (?)", pg.In(chainInfoIDs)).
+		Where("period = ?", period).
+		Where("bucket_start >= ?", from).
+		Where("bucket_start < ?", to)
+	if msgType != "" {
+		q = q.Where("msg_type = ?", msgType)
+	}
+
+	err := q.
+		Order("bucket_start ASC", "msg_type ASC", "chain_info_id ASC").
+		Select()
+
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return stats, nil
+		}
+		return stats, err
+	}
+
+	return stats, nil
+}
+
+// GetGasSummaries returns the distribution of gas used per message type of the samples of the chains since the time.
+func (db *Database) GetGasSummaries(chainInfoIDs []int, since time.Time) ([]GasSummary, error) {
+	summaries := make([]GasSummary, 0)
+	_, err := db.Query(&summaries, `SELECT msg_type, `+gasDistribution+`
+FROM tx_gas
+WHERE chain_info_id IN (?) AND timestamp >= ?
+GROUP BY msg_type
+ORDER BY num_txs DESC`, pg.In(chainInfoIDs), since)
+
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return summaries, nil
+		}
+		return summaries, err
+	}
+
+	return summaries, nil
+}
@@ -0,0 +1,64 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	pg "github.com/go-pg/pg/v10"
+)
+
+// GasPrice is the gas price recommended for a fee denom from the transactions of the last blocks up to Height.
+// Prices are decimal amounts of the denom per unit of gas.
+type GasPrice struct {
+	tableName struct{} `pg:"gas_price"`
+
+	ChainInfoID int       `pg:",pk,use_zero"`
+	Denom       string    `pg:",pk"`
+	Height      int64     `pg:",notnull,use_zero"`
+	NumTxs      int64     `pg:",notnull,use_zero"`
+	Slow        string    `pg:",notnull"`
+	Average     string    `pg:",notnull"`
+	Fast        string    `pg:",notnull"`
+	MinGasPrice string    `pg:",notnull,use_zero"`
+	Timestamp   time.Time `pg:"default:now()"`
+}
+
+// ReplaceGasPrices replaces the gas prices of the chain, so that denoms no longer paid in the last blocks are removed.
+func (db *Database) ReplaceGasPrices(chainInfoID int, prices []GasPrice) error {
+	return db.RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		_, err := tx.Model((*GasPrice)(nil)).
+			Where("chain_info_id = ?", chainInfoID).
+			Delete()
+		if err != nil {
+			return err
+		}
+
+		if len(prices) > 0 {
+			if _, err := tx.Model(&prices).Insert(); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetGasPrices returns the gas prices of the chains in order of denom.
+// A denom priced by several chains gets the prices of the chain which priced it at the highest height.
+func (db *Database) GetGasPrices(chainInfoIDs []int) ([]GasPrice, error) {
+	prices := make([]GasPrice, 0)
+	err := db.Model(&prices).
+		DistinctOn("denom").
+		Where("chain_info_id IN (?)", pg.In(chainInfoIDs)).
+		Order("denom ASC", "height DESC").
+		Select()
+
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return prices, nil
+		}
+		return prices, err
+	}
+
+	return prices, nil
+}
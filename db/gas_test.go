@@ -0,0 +1,74 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pg "github.com/go-pg/pg/v10"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBucketStart(t *testing.T) {
+	kst := time.FixedZone("KST", 9*60*60)
+	ts := time.Date(2023, 10, 2, 8, 30, 15, 0, kst) // 2023-10-01 23:30:15 UTC
+
+	hour := BucketStart(PERIOD_HOUR, ts)
+	require.Equal(t, time.Date(2023, 10, 1, 23, 0, 0, 0, time.UTC), hour)
+	require.Equal(t, hour.Add(time.Hour), bucketEnd(PERIOD_HOUR, hour))
+
+	day := BucketStart(PERIOD_DAY, ts)
+	require.Equal(t, time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC), day)
+	require.Equal(t, day.Add(24*time.Hour), bucketEnd(PERIOD_DAY, day))
+}
+
+func TestRefreshGasStats(t *testing.T) {
+	chainInfoID := 1 << 30 // no chain has the id, so only the rows of the test are removed
+	defer func() {
+		for _, model := range []interface{}{(*TxGas)(nil), (*GasStat)(nil), (*IndexCursor)(nil)} {
+			_, err := db.Model(model).Where("chain_info_id = ?", chainInfoID).Delete()
+			require.NoError(t, err)
+		}
+	}()
+
+	day := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	samples := []TxGas{
+		{ChainInfoID: chainInfoID, TxID: 1, MsgType: "send", Height: 10, GasWanted: 200, GasUsed: 100, Fee: "10", Timestamp: day.Add(10 * time.Minute)},
+		{ChainInfoID: chainInfoID, TxID: 2, MsgType: "send", Height: 10, GasWanted: 400, GasUsed: 200, Fee: "20", Timestamp: day.Add(10 * time.Minute)},
+		{ChainInfoID: chainInfoID, TxID: 3, MsgType: "send", Height: 11, GasWanted: 600, GasUsed: 300, Timestamp: day.Add(20 * time.Minute)},
+		{ChainInfoID: chainInfoID, TxID: 4, MsgType: "vote", Height: 20, GasWanted: 100, GasUsed: 50, Fee: "5", Timestamp: day.Add(2 * time.Hour)},
+	}
+	require.NoError(t, db.SaveTxGas(chainInfoID, "test_gas", 4, samples))
+	require.NoError(t, db.RefreshGasStats(chainInfoID, "test_gas_refresh", 0, 4))
+
+	hours, err := db.GetGasStats([]int{chainInfoID}, PERIOD_HOUR, "", day, day.Add(24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, hours, 2)
+	require.Equal(t, "send", hours[0].MsgType)
+	require.True(t, day.Equal(hours[0].BucketStart))
+	require.Equal(t, int64(3), hours[0].NumTxs)
+	require.Equal(t, int64(200), hours[0].GasUsedP50)
+	require.Equal(t, int64(200), hours[0].GasUsedAvg)
+	require.Equal(t, int64(400), hours[0].GasWantedAvg)
+	require.Equal(t, "15", hours[0].FeeAvg) // fees paid in other denoms are not averaged
+
+	days, err := db.GetGasStats([]int{chainInfoID}, PERIOD_DAY, "", day, day.Add(24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, days, 2)
+
+	cursor, err := db.GetIndexCursor(chainInfoID, "test_gas_refresh")
+	require.NoError(t, err)
+	require.Equal(t, int64(4), cursor)
+
+	// stats of the deleted samples are removed with them
+	err = db.RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		return deleteTxGas(tx, chainInfoID, "height >= ?", 20)
+	})
+	require.NoError(t, err)
+
+	days, err = db.GetGasStats([]int{chainInfoID}, PERIOD_DAY, "", day, day.Add(24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, days, 1)
+	require.Equal(t, "send", days[0].MsgType)
+	require.Equal(t, int64(3), days[0].NumTxs)
+}
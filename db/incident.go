@@ -0,0 +1,82 @@
+package db
+
+import (
+	"time"
+
+	pg "github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
+)
+
+// BlockIncident records a block whose parent hash did not match the block stored at height-1
+// and how the exporter reacted to it.
+type BlockIncident struct {
+	tableName struct{} `pg:"block_incident"`
+
+	ID                 int64     `pg:",pk"`
+	ChainID            string    `pg:",notnull"`
+	Height             int64     `pg:",notnull,use_zero"`
+	StoredParentHash   string    `pg:",notnull"`
+	ReportedParentHash string    `pg:",notnull"`
+	ForkHeight         int64     `pg:",use_zero"`
+	Action             string    `pg:",notnull"`
+	Timestamp          time.Time `pg:"default:now()"`
+}
+
+// CreateLocalTables creates tables and indexes which are owned by this exporter rather than mintscan-database.
+func (db *Database) CreateLocalTables() error {
+	models := []interface{}{
+		(*BlockIncident)(nil),
+		(*EventCursor)(nil),
+		(*OutboxEntry)(nil),
+		(*BlockEvent)(nil),
+		(*BlockEventAttribute)(nil),
+		(*TxEvent)(nil),
+		(*IndexCursor)(nil),
+		(*TxFeePayer)(nil),
+		(*TxGas)(nil),
+		(*GasStat)(nil),
+		(*GasPrice)(nil),
+	}
+
+	for _, model := range models {
+		err := db.Model(model).CreateTable(&orm.CreateTableOptions{
+			IfNotExists: true,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, index := range append(append(blockEventIndexes, txEventIndexes...), gasIndexes...) {
+		if _, err := db.Exec(index); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// InsertBlockIncident saves a block incident.
+func (db *Database) InsertBlockIncident(incident *BlockIncident) error {
+	_, err := db.Model(incident).Insert()
+	return err
+}
+
+// GetBlockIncidents returns the latest block incidents of the chain.
+func (db *Database) GetBlockIncidents(chainID string, limit int) ([]BlockIncident, error) {
+	incidents := make([]BlockIncident, 0)
+	err := db.Model(&incidents).
+		Where("chain_id = ?", chainID).
+		Order("id DESC").
+		Limit(limit).
+		Select()
+
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return incidents, nil
+		}
+		return incidents, err
+	}
+
+	return incidents, nil
+}
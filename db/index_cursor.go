@@ -0,0 +1,92 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	pg "github.com/go-pg/pg/v10"
+
+	mdschema "github.com/cosmostation/mintscan-database/schema"
+)
+
+// IndexCursor is the id of the last transaction an indexer of this exporter has processed.
+type IndexCursor struct {
+	tableName struct{} `pg:"index_cursor"`
+
+	ChainInfoID int       `pg:",pk,use_zero"`
+	Name        string    `pg:",pk"`
+	Pointer     int64     `pg:",notnull,use_zero"`
+	Timestamp   time.Time `pg:"default:now()"`
+}
+
+// GetIndexCursor returns the pointer of the indexer, 0 when the indexer has not processed any transaction.
+func (db *Database) GetIndexCursor(chainInfoID int, name string) (int64, error) {
+	var cursor IndexCursor
+	err := db.Model(&cursor).
+		Where("chain_info_id = ?", chainInfoID).
+		Where("name = ?", name).
+		Select()
+
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	return cursor.Pointer, nil
+}
+
+// SetIndexCursor saves the pointer of the indexer.
+func (db *Database) SetIndexCursor(chainInfoID int, name string, pointer int64) error {
+	return db.RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		return setIndexCursor(tx, chainInfoID, name, pointer)
+	})
+}
+
+// setIndexCursor saves the pointer of the indexer in the transaction.
+func setIndexCursor(tx *pg.Tx, chainInfoID int, name string, pointer int64) error {
+	cursor := &IndexCursor{
+		ChainInfoID: chainInfoID,
+		Name:        name,
+		Pointer:     pointer,
+		Timestamp:   time.Now(),
+	}
+	_, err := tx.Model(cursor).
+		OnConflict("(chain_info_id, name) DO UPDATE").
+		Set("pointer = EXCLUDED.pointer, timestamp = EXCLUDED.timestamp").
+		Insert()
+	return err
+}
+
+// lowerIndexCursor moves the pointer of the indexer back to pointer in the transaction when it is beyond it.
+func lowerIndexCursor(tx *pg.Tx, chainInfoID int, name string, pointer int64) error {
+	_, err := tx.Model((*IndexCursor)(nil)).
+		Set("pointer = ?", pointer).
+		Set("timestamp = ?", time.Now()).
+		Where("chain_info_id = ?", chainInfoID).
+		Where("name = ?", name).
+		Where("pointer > ?", pointer).
+		Update()
+	return err
+}
+
+// GetTransactionsAfter returns the transactions of the chain whose id is greater than txID in ascending order of id.
+func (db *Database) GetTransactionsAfter(chainInfoID int, txID int64, limit int) ([]mdschema.Transaction, error) {
+	txs := make([]mdschema.Transaction, 0)
+	err := db.Model(&txs).
+		Where("chain_info_id = ?", chainInfoID).
+		Where("id > ?", txID).
+		Order("id ASC").
+		Limit(limit).
+		Select()
+
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return txs, nil
+		}
+		return txs, err
+	}
+
+	return txs, nil
+}
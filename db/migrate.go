@@ -0,0 +1,111 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+
+	pg "github.com/go-pg/pg/v10"
+	"go.uber.org/zap"
+)
+
+// chunkStore is the part of a database connection used to migrate chunks.
+type chunkStore interface {
+	Query(model, query interface{}, params ...interface{}) (pg.Result, error)
+	QueryOne(model, query interface{}, params ...interface{}) (pg.Result, error)
+	Exec(query interface{}, params ...interface{}) (pg.Result, error)
+}
+
+// MigrateChunks compresses chunks of the transaction table which were stored without compression, batchSize rows at a time.
+// alterColumn allows converting the chunk column to bytea, see ensureByteaChunk.
+func (db *Database) MigrateChunks(batchSize int, alterColumn bool) error {
+	return migrateChunks(db, "transaction", batchSize, alterColumn)
+}
+
+// MigrateChunks compresses chunks of raw_block and raw_transaction tables which were stored without compression, batchSize rows at a time.
+// alterColumn allows converting the chunk columns to bytea, see ensureByteaChunk.
+func (db *RawDatabase) MigrateChunks(batchSize int, alterColumn bool) error {
+	for _, table := range []string{"raw_block", "raw_transaction"} {
+		if err := migrateChunks(db, table, batchSize, alterColumn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateChunks converts the chunk column of the table to bytea and compresses every chunk without a version marker.
+// Rows are visited in id order and compressed rows are skipped, so it can be stopped and run again.
+func migrateChunks(s chunkStore, table string, batchSize int, alterColumn bool) error {
+	if err := ensureByteaChunk(s, table, alterColumn); err != nil {
+		return err
+	}
+
+	var afterID int64
+	var compressed int
+	for {
+		var rows []struct {
+			ID    int64
+			Chunk []byte
+		}
+		_, err := s.Query(&rows, "SELECT id, chunk FROM ? WHERE id > ? ORDER BY id LIMIT ?", pg.Ident(table), afterID, batchSize)
+		if err != nil {
+			return fmt.Errorf("failed to get chunks of %s: %s", table, err)
+		}
+		if len(rows) == 0 {
+			break
+		}
+
+		values := make([]string, 0, len(rows))
+		params := []interface{}{pg.Ident(table)}
+		for _, r := range rows {
+			if len(r.Chunk) == 0 || IsCompressedChunk(r.Chunk) {
+				continue
+			}
+			values = append(values, "(CAST(? AS bigint), CAST(? AS bytea))")
+			params = append(params, r.ID, CompressChunk(r.Chunk))
+		}
+
+		// the chunks of a batch are updated by a single statement, so a batch is compressed entirely or not at all
+		afterID = rows[len(rows)-1].ID
+		if len(values) > 0 {
+			_, err := s.Exec("UPDATE ? AS t SET chunk = v.chunk FROM (VALUES "+strings.Join(values, ", ")+") AS v (id, chunk) WHERE t.id = v.id", params...)
+			if err != nil {
+				return fmt.Errorf("failed to compress chunks of %s up to id %d: %s", table, afterID, err)
+			}
+			compressed += len(values)
+		}
+
+		zap.S().Infof("migrate chunks of %s : id %d, compressed %d", table, afterID, compressed)
+	}
+
+	return nil
+}
+
+// ensureByteaChunk converts the chunk column of the table to bytea when it holds JSON or text,
+// since compressed chunks are not valid JSON.
+//
+// The conversion rewrites the whole table under an ACCESS EXCLUSIVE lock, which blocks every read
+// and write of the table until it finishes, so it takes as long as copying the table. It is done
+// only when alterColumn is set, otherwise an error is returned so that the conversion can be
+// scheduled in a maintenance window.
+func ensureByteaChunk(s chunkStore, table string, alterColumn bool) error {
+	var dataType string
+	_, err := s.QueryOne(pg.Scan(&dataType), `SELECT data_type FROM information_schema.columns
+		WHERE table_schema = ANY(current_schemas(false)) AND table_name = ? AND column_name = 'chunk'
+		ORDER BY array_position(current_schemas(false), table_schema::name) LIMIT 1`, table)
+	if err != nil {
+		return fmt.Errorf("failed to get type of chunk column of %s: %s", table, err)
+	}
+	if dataType == "bytea" {
+		return nil
+	}
+	if !alterColumn {
+		return fmt.Errorf("chunk column of %s is %s, converting it to bytea locks and rewrites the table, run again with --migrate-alter-columns to convert it", table, dataType)
+	}
+
+	zap.S().Infof("convert chunk column of %s from %s to bytea", table, dataType)
+	_, err = s.Exec("ALTER TABLE ? ALTER COLUMN chunk TYPE bytea USING convert_to(chunk::text, 'UTF8')", pg.Ident(table))
+	if err != nil {
+		return fmt.Errorf("failed to convert chunk column of %s: %s", table, err)
+	}
+	return nil
+}
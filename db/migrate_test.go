@@ -0,0 +1,63 @@
+package db
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	pg "github.com/go-pg/pg/v10"
+	"github.com/go-pg/pg/v10/orm"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeChunkStore serves the chunks of a table one batch at a time and records the statements it executes.
+type fakeChunkStore struct {
+	batches [][]fakeChunkRow
+	execs   []string
+}
+
+type fakeChunkRow struct {
+	id    int64
+	chunk []byte
+}
+
+func (s *fakeChunkStore) Query(model, query interface{}, params ...interface{}) (pg.Result, error) {
+	if len(s.batches) == 0 {
+		return nil, nil
+	}
+	rows := reflect.ValueOf(model).Elem()
+	for _, r := range s.batches[0] {
+		row := reflect.New(rows.Type().Elem()).Elem()
+		row.Field(0).SetInt(r.id)
+		row.Field(1).SetBytes(r.chunk)
+		rows.Set(reflect.Append(rows, row))
+	}
+	s.batches = s.batches[1:]
+	return nil, nil
+}
+
+func (s *fakeChunkStore) QueryOne(model, query interface{}, params ...interface{}) (pg.Result, error) {
+	return nil, nil
+}
+
+func (s *fakeChunkStore) Exec(query interface{}, params ...interface{}) (pg.Result, error) {
+	s.execs = append(s.execs, string(orm.NewFormatter().FormatQuery(nil, query.(string), params...)))
+	return nil, nil
+}
+
+func TestMigrateChunksUpdatesBatch(t *testing.T) {
+	json := []byte(`{"height":"1"}`)
+	s := &fakeChunkStore{batches: [][]fakeChunkRow{
+		{{1, json}, {2, CompressChunk(json)}, {3, nil}, {4, json}},
+		{{5, CompressChunk(json)}},
+	}}
+	require.NoError(t, migrateChunks(s, "transaction", 4, true))
+
+	// the column is converted, then each batch with chunks to compress is updated by one statement
+	require.Len(t, s.execs, 2)
+	require.True(t, strings.HasPrefix(s.execs[0], `ALTER TABLE "transaction"`))
+	require.True(t, strings.HasPrefix(s.execs[1], `UPDATE "transaction" AS t SET chunk = v.chunk FROM (VALUES (CAST(1 AS bigint), CAST('\x`))
+	require.Contains(t, s.execs[1], `(CAST(4 AS bigint), CAST('\x`)
+	require.NotContains(t, s.execs[1], "CAST(2 AS bigint)")
+	require.NotContains(t, s.execs[1], "CAST(3 AS bigint)")
+}
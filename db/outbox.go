@@ -0,0 +1,92 @@
+package db
+
+import (
+	"encoding/json"
+	"time"
+
+	pg "github.com/go-pg/pg/v10"
+)
+
+// Status of an outbox entry.
+const (
+	OUTBOX_PENDING = "pending"
+	OUTBOX_DONE    = "done"
+	OUTBOX_FAILED  = "failed"
+	OUTBOX_DROPPED = "dropped"
+)
+
+// OutboxEntry is a notification waiting to be delivered for an exported block.
+// It is delivered only after the block with the same height and hash is committed,
+// and dropped when a different block is committed at the height.
+type OutboxEntry struct {
+	tableName struct{} `pg:"outbox,alias:outbox"`
+
+	ID            int64           `pg:",pk"`
+	ChainID       string          `pg:",notnull"`
+	Height        int64           `pg:",notnull,use_zero"`
+	BlockHash     string          `pg:",notnull"`
+	Kind          string          `pg:",notnull"`
+	DedupKey      string          `pg:",notnull,unique"`
+	Payload       json.RawMessage `pg:"type:jsonb"`
+	Status        string          `pg:",notnull"`
+	Attempts      int             `pg:",use_zero"`
+	LastError     string
+	NextAttemptAt time.Time `pg:"default:now()"`
+	Timestamp     time.Time `pg:"default:now()"`
+}
+
+// insertOutboxEntries saves entries which are not saved yet. Entries of a block exported again are ignored by their dedup key.
+func insertOutboxEntries(tx *pg.Tx, entries []OutboxEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	_, err := tx.Model(&entries).
+		OnConflict("(dedup_key) DO NOTHING").
+		Insert()
+	return err
+}
+
+// GetDeliverableOutboxEntries returns pending entries whose block is committed and whose next attempt is due.
+func (db *Database) GetDeliverableOutboxEntries(chainInfoID int, chainID string, limit int) ([]OutboxEntry, error) {
+	entries := make([]OutboxEntry, 0)
+	err := db.Model(&entries).
+		Where("outbox.chain_id = ?", chainID).
+		Where("outbox.status = ?", OUTBOX_PENDING).
+		Where("outbox.next_attempt_at <= now()").
+		Where("EXISTS (SELECT 1 FROM block AS b WHERE b.chain_info_id = ? AND b.height = outbox.height AND b.hash = outbox.block_hash)", chainInfoID).
+		Order("outbox.id ASC").
+		Limit(limit).
+		Select()
+
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return entries, nil
+		}
+		return entries, err
+	}
+
+	return entries, nil
+}
+
+// DropOrphanedOutboxEntries marks pending entries as dropped when a block with a different hash is committed at their height.
+func (db *Database) DropOrphanedOutboxEntries(chainInfoID int, chainID string) (int, error) {
+	res, err := db.Model((*OutboxEntry)(nil)).
+		Set("status = ?", OUTBOX_DROPPED).
+		Where("outbox.chain_id = ?", chainID).
+		Where("outbox.status = ?", OUTBOX_PENDING).
+		Where("EXISTS (SELECT 1 FROM block AS b WHERE b.chain_info_id = ? AND b.height = outbox.height AND b.hash <> outbox.block_hash)", chainInfoID).
+		Update()
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected(), nil
+}
+
+// UpdateOutboxEntry saves the status, attempts and next attempt of an entry.
+func (db *Database) UpdateOutboxEntry(entry *OutboxEntry) error {
+	_, err := db.Model(entry).
+		Column("status", "attempts", "last_error", "next_attempt_at").
+		WherePK().
+		Update()
+	return err
+}
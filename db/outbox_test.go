@@ -0,0 +1,60 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	mdschema "github.com/cosmostation/mintscan-database/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertExportedDataSavesOutboxWithBlock(t *testing.T) {
+	chainInfos, err := db.GetChainInfo()
+	require.NoError(t, err)
+	require.NotEmpty(t, chainInfos)
+	chainInfo := chainInfos[0]
+	chainInfoID := int(chainInfo.ID)
+
+	height := int64(1 << 40)
+	block := &mdschema.Block{
+		ChainInfoID: chainInfoID,
+		Height:      height,
+		Hash:        "OUTBOXTEST",
+		ParentHash:  "genesis",
+		Timestamp:   time.Now().UTC(),
+	}
+	e := &ExportedData{
+		BasicData: &mdschema.BasicData{Block: block},
+		Outbox: []OutboxEntry{{
+			ChainID:   chainInfo.ChainID,
+			Height:    height,
+			BlockHash: block.Hash,
+			DedupKey:  "test/outbox",
+			Status:    OUTBOX_PENDING,
+		}},
+	}
+	defer func() {
+		require.NoError(t, db.DeleteBlocksFrom(chainInfoID, chainInfo.ChainID, height))
+		require.NoError(t, db.SetIndexCursor(chainInfoID, RawRollbackCursor, 0))
+		_, err := db.Model((*OutboxEntry)(nil)).Where("dedup_key = ?", "test/outbox").Delete()
+		require.NoError(t, err)
+	}()
+
+	// an entry without a kind violates not null, so the block must not be saved either
+	require.Error(t, db.InsertExportedData(e))
+	hash, err := db.GetBlockHash(chainInfoID, height)
+	require.NoError(t, err)
+	require.Empty(t, hash)
+
+	block.ID = 0
+	e.Outbox[0].Kind = "test"
+	require.NoError(t, db.InsertExportedData(e))
+
+	entries, err := db.GetDeliverableOutboxEntries(chainInfoID, chainInfo.ChainID, 1000)
+	require.NoError(t, err)
+	var found bool
+	for _, entry := range entries {
+		found = found || entry.DedupKey == "test/outbox"
+	}
+	require.True(t, found)
+}
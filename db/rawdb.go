@@ -1,6 +1,9 @@
 package db
 
 import (
+	"context"
+
+	pg "github.com/go-pg/pg/v10"
 
 	//mbl
 	mblconfig "github.com/cosmostation/mintscan-backend-library/config"
@@ -21,8 +24,86 @@ func RawDBConnect(dbcfg *mblconfig.DatabaseConfig) *RawDatabase {
 	return &RawDatabase{db}
 }
 
+// RawDBConnectChain opens raw database connections to the schemas of another chain indexed by the same process.
+// Every chain needs a pool of its own, as in ConnectChain, and the schemas of the package set by RawDBConnect are restored.
+func RawDBConnectChain(dbcfg *mblconfig.DatabaseConfig) *RawDatabase {
+	commonSchema, chainSchema := mdschema.GetCommonSchema(), mdschema.GetChainSchema()
+	defer func() {
+		mdschema.SetCommonSchema(commonSchema)
+		mdschema.SetChainSchema(chainSchema)
+	}()
+
+	db := mdrawdb.Connect(dbcfg.Host, dbcfg.Port, dbcfg.User, dbcfg.Password, dbcfg.DBName, dbcfg.CommonSchema, dbcfg.ChainSchema, dbcfg.Timeout)
+	return &RawDatabase{db}
+}
+
 // CreateTables creates database tables using ORM (Object Relational Mapper).
 func (db *RawDatabase) CreateTablesAndIndexes() {
 	// 생성 오류 시 패닉
 	db.CreateTables()
 }
+
+// InsertRawData inserts the raw block and its raw transactions in a single transaction,
+// so that a height is never stored without its transactions. Chunks are compressed when compression is enabled.
+func (db *RawDatabase) InsertRawData(e *mdschema.RawData) error {
+	e = compressRawData(e)
+	return db.RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		if _, err := tx.Model(e.Block).Insert(); err != nil {
+			return err
+		}
+
+		if len(e.Transactions) > 0 {
+			if _, err := tx.Model(&e.Transactions).Insert(); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetRawDataInRange returns the raw blocks of the chain from height from to height to in height order
+// with their raw transactions. Chunks are returned decompressed.
+func (db *RawDatabase) GetRawDataInRange(chainID string, from, to int64) ([]mdschema.RawData, error) {
+	var blocks []mdschema.RawBlock
+	err := db.Model(&blocks).
+		Where("chain_id = ?", chainID).
+		Where("height BETWEEN ? AND ?", from, to).
+		Order("height ASC").
+		Select()
+	if err != nil {
+		return nil, err
+	}
+
+	var txs []mdschema.RawTransaction
+	err = db.Model(&txs).
+		Where("chain_id = ?", chainID).
+		Where("height BETWEEN ? AND ?", from, to).
+		Order("height ASC", "id ASC").
+		Select()
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]mdschema.RawData, len(blocks))
+	index := make(map[int64]int, len(blocks))
+	for i := range blocks {
+		if blocks[i].Chunk, err = DecompressChunk(blocks[i].Chunk); err != nil {
+			return nil, err
+		}
+		data[i].Block = &blocks[i]
+		index[blocks[i].Height] = i
+	}
+	for _, tx := range txs {
+		i, ok := index[tx.Height]
+		if !ok {
+			continue
+		}
+		if tx.Chunk, err = DecompressChunk(tx.Chunk); err != nil {
+			return nil, err
+		}
+		data[i].Transactions = append(data[i].Transactions, tx)
+	}
+
+	return data, nil
+}
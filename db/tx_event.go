@@ -0,0 +1,115 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	pg "github.com/go-pg/pg/v10"
+
+	mdschema "github.com/cosmostation/mintscan-database/schema"
+)
+
+// TxEvent is an attribute of an event emitted by a transaction. MsgIndex is the index of the message
+// which emitted the event for events of the logs, -1 for events of the transaction such as fee payment.
+type TxEvent struct {
+	tableName struct{} `pg:"tx_event,alias:tx_event"`
+
+	ChainInfoID int    `pg:",pk,use_zero"`
+	TxID        int64  `pg:",pk,use_zero"`
+	MsgIndex    int    `pg:",pk,use_zero"`
+	EventIndex  int    `pg:",pk,use_zero"`
+	Position    int    `pg:",pk,use_zero"`
+	Height      int64  `pg:",notnull,use_zero"`
+	Type        string `pg:",notnull"`
+	Key         string `pg:",notnull,use_zero"`
+	Value       string `pg:",notnull,use_zero"`
+}
+
+// TxEventPredicate matches transactions which emitted an event of the type.
+// Key and Value are ignored when empty.
+type TxEventPredicate struct {
+	Type  string
+	Key   string
+	Value string
+}
+
+// txEventIndexes are created with the tx event table to search transactions by event type and attribute.
+// Values are indexed by their md5 hash like attributes of block events, and matched with md5(value) = md5(?).
+var txEventIndexes = []string{
+	"DROP INDEX IF EXISTS tx_event_type_key_value_idx",
+	"CREATE INDEX IF NOT EXISTS tx_event_type_key_value_hash_idx ON tx_event (chain_info_id, type, key, md5(value), tx_id DESC)",
+	"CREATE INDEX IF NOT EXISTS tx_event_height_idx ON tx_event (chain_info_id, height)",
+}
+
+// SaveTxEvents saves the events and moves the cursor of the indexer to pointer in a single transaction.
+// Events of transactions indexed again are ignored.
+func (db *Database) SaveTxEvents(chainInfoID int, name string, pointer int64, events []TxEvent) error {
+	return db.RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		if len(events) > 0 {
+			_, err := tx.Model(&events).
+				OnConflict("DO NOTHING").
+				Insert()
+			if err != nil {
+				return err
+			}
+		}
+
+		return setIndexCursor(tx, chainInfoID, name, pointer)
+	})
+}
+
+// deleteTxEvents deletes the events of transactions at the height and above in the transaction.
+func deleteTxEvents(tx *pg.Tx, chainInfoID int, condition string, height int64) error {
+	_, err := tx.Model((*TxEvent)(nil)).
+		Where("chain_info_id = ?", chainInfoID).
+		Where(condition, height).
+		Delete()
+	return err
+}
+
+// SearchTransactions returns the transactions of the chains which match every predicate in descending order of id.
+// Only transactions whose id is less than beforeTxID are returned when beforeTxID is not 0. Chunks are returned decompressed.
+func (db *Database) SearchTransactions(chainInfoIDs []int, predicates []TxEventPredicate, beforeTxID int64, limit int) ([]mdschema.Transaction, error) {
+	txs := make([]mdschema.Transaction, 0)
+	q := db.Model(&txs).
+		Where("chain_info_id IN (?)", pg.In(chainInfoIDs))
+	if beforeTxID > 0 {
+		q = q.Where("id < ?", beforeTxID)
+	}
+	// each predicate joins the ids of the transactions which emitted a matching event, which are the only column of the join
+	for i, p := range predicates {
+		sub := db.Model((*TxEvent)(nil)).
+			ColumnExpr("DISTINCT tx_id").
+			Where("chain_info_id IN (?)", pg.In(chainInfoIDs)).
+			Where("type = ?", p.Type)
+		if p.Key != "" {
+			sub = sub.Where("key = ?", p.Key)
+		}
+		if p.Value != "" {
+			sub = sub.Where("md5(value) = md5(?)", p.Value)
+		}
+		alias := fmt.Sprintf("e%d", i)
+		q = q.Join("JOIN (?) AS ?", sub, pg.Ident(alias)).
+			JoinOn("?.tx_id = id", pg.Ident(alias))
+	}
+
+	err := q.
+		Order("id DESC").
+		Limit(limit).
+		Select()
+
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return txs, nil
+		}
+		return txs, err
+	}
+
+	for i := range txs {
+		if txs[i].Chunk, err = DecompressChunk(txs[i].Chunk); err != nil {
+			return nil, err
+		}
+	}
+
+	return txs, nil
+}
@@ -3,29 +3,29 @@ package db
 import (
 	"strings"
 
-	sdktypes "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmostation/mintscan-database/schema"
 	pg "github.com/go-pg/pg/v10"
 )
 
-// GetValidatorByAnyAddr returns a validator information by any type of address format
-func (db *Database) GetValidatorByAnyAddr(anyAddr string) (schema.Validator, error) {
+// GetValidatorByAnyAddr returns a validator information by any type of address format.
+// Bech32 addresses are recognized by the account address prefix of the network of the chain.
+func (db *Database) GetValidatorByAnyAddr(addressPrefix, anyAddr string) (schema.Validator, error) {
 	var val schema.Validator
 	var err error
 
 	switch {
 	// jeonghwan
-	case strings.HasPrefix(anyAddr, sdktypes.GetConfig().GetBech32ConsensusPubPrefix()): // Bech32 prefix for validator public key
+	case strings.HasPrefix(anyAddr, addressPrefix+"valconspub"): // Bech32 prefix for validator public key
 		err = db.Model(&val).
 			Where("consensus_pubkey = ?", anyAddr).
 			Limit(1).
 			Select()
-	case strings.HasPrefix(anyAddr, sdktypes.GetConfig().GetBech32ValidatorAddrPrefix()): // Bech32 prefix for validator address
+	case strings.HasPrefix(anyAddr, addressPrefix+"valoper"): // Bech32 prefix for validator address
 		err = db.Model(&val).
 			Where("operator_address = ?", anyAddr).
 			Limit(1).
 			Select()
-	case strings.HasPrefix(anyAddr, sdktypes.GetConfig().GetBech32AccountAddrPrefix()): // Bech32 prefix for account address
+	case strings.HasPrefix(anyAddr, addressPrefix): // Bech32 prefix for account address
 		err = db.Model(&val).
 			Where("address = ?", anyAddr).
 			Limit(1).
@@ -0,0 +1,48 @@
+// Package event publishes what the exporter committed for each height to a message broker.
+package event
+
+import (
+	"context"
+	"time"
+
+	mdschema "github.com/cosmostation/mintscan-database/schema"
+)
+
+// BlockEventVersion is the version of BlockEvent. It changes whenever a field changes meaning or is removed.
+const BlockEventVersion = 1
+
+// BlockEvent is published after the data of a height is committed.
+type BlockEvent struct {
+	Version     int                          `json:"version"`
+	ChainID     string                       `json:"chain_id"`
+	Height      int64                        `json:"height"`
+	Hash        string                       `json:"hash"`
+	ParentHash  string                       `json:"parent_hash"`
+	Proposer    string                       `json:"proposer"`
+	NumTxs      int                          `json:"num_txs"`
+	Timestamp   time.Time                    `json:"timestamp"`
+	Txs         []TxSummary                  `json:"txs"`
+	TMAs        []mdschema.TMA               `json:"tmas"`
+	Proposals   []mdschema.Proposal          `json:"proposals"`
+	Deposits    []mdschema.Deposit           `json:"deposits"`
+	Votes       []mdschema.Vote              `json:"votes"`
+	PowerEvents []mdschema.PowerEventHistory `json:"power_events"`
+}
+
+// TxSummary is the decoded summary of a transaction in BlockEvent.
+type TxSummary struct {
+	Hash      string   `json:"hash"`
+	Code      uint32   `json:"code"`
+	Codespace string   `json:"codespace,omitempty"`
+	GasWanted int64    `json:"gas_wanted"`
+	GasUsed   int64    `json:"gas_used"`
+	Messages  []string `json:"messages"`
+	Memo      string   `json:"memo,omitempty"`
+}
+
+// Publisher delivers a message to a subject of a broker.
+// Publish returns nil only after the broker has accepted the message.
+type Publisher interface {
+	Publish(ctx context.Context, subject string, payload []byte) error
+	Close() error
+}
@@ -0,0 +1,43 @@
+package event
+
+import (
+	"context"
+	"sync"
+)
+
+// Message is a message kept by Memory.
+type Message struct {
+	Subject string
+	Payload []byte
+}
+
+// Memory keeps published messages in memory. It is meant for tests.
+type Memory struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+// NewMemory returns an empty in-memory publisher.
+func NewMemory() *Memory {
+	return new(Memory)
+}
+
+// Publish implements Publisher.
+func (m *Memory) Publish(ctx context.Context, subject string, payload []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = append(m.messages, Message{Subject: subject, Payload: append([]byte(nil), payload...)})
+	return nil
+}
+
+// Close implements Publisher.
+func (m *Memory) Close() error {
+	return nil
+}
+
+// Messages returns the messages published so far in order.
+func (m *Memory) Messages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Message(nil), m.messages...)
+}
@@ -0,0 +1,207 @@
+package event
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeJetStream is a local stand-in of a NATS server with a JetStream stream bound to the subject "blocks".
+// Messages of the stream are recorded and acknowledged, and messages of other subjects are rejected.
+type fakeJetStream struct {
+	ln net.Listener
+
+	mu       sync.Mutex
+	messages []Message
+	connects []string
+	conns    []net.Conn
+}
+
+func newFakeJetStream(t *testing.T) *fakeJetStream {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := &fakeJetStream{ln: ln}
+	go s.serve()
+	t.Cleanup(func() {
+		ln.Close()
+		s.drop()
+	})
+	return s
+}
+
+func (s *fakeJetStream) url(userinfo string) string {
+	return "nats://" + userinfo + s.ln.Addr().String()
+}
+
+func (s *fakeJetStream) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns = append(s.conns, conn)
+		s.mu.Unlock()
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeJetStream) handle(conn net.Conn) {
+	defer conn.Close()
+	fmt.Fprintf(conn, "INFO {\"server_id\":\"fake\",\"version\":\"2.10.0\",\"proto\":1,\"max_payload\":64,\"auth_required\":true}\r\n")
+
+	var mu sync.Mutex
+	write := func(format string, args ...interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+		fmt.Fprintf(conn, format, args...)
+	}
+
+	sids := make(map[string]string) // subject pattern of a subscription by its sid
+	r := bufio.NewReader(conn)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case "CONNECT":
+			s.mu.Lock()
+			s.connects = append(s.connects, line)
+			s.mu.Unlock()
+		case "PING":
+			write("PONG\r\n")
+		case "SUB":
+			sids[fields[len(fields)-1]] = fields[1]
+		case "PUB":
+			var subject, reply string
+			var size int
+			if len(fields) == 4 {
+				subject, reply = fields[1], fields[2]
+			} else {
+				subject = fields[1]
+			}
+			fmt.Sscanf(fields[len(fields)-1], "%d", &size)
+			payload := make([]byte, size+2)
+			if _, err := io.ReadFull(r, payload); err != nil {
+				return
+			}
+
+			ack := `{"error":{"code":503,"description":"no stream"}}`
+			if subject == "blocks" {
+				s.mu.Lock()
+				s.messages = append(s.messages, Message{Subject: subject, Payload: payload[:size]})
+				ack = fmt.Sprintf(`{"stream":"BLOCKS","seq":%d}`, len(s.messages))
+				s.mu.Unlock()
+			}
+			if reply == "" {
+				continue
+			}
+			for sid, pattern := range sids {
+				if strings.HasSuffix(pattern, ".*") && strings.HasPrefix(reply, strings.TrimSuffix(pattern, "*")) {
+					write("MSG %s %s %d\r\n%s\r\n", reply, sid, len(ack), ack)
+				}
+			}
+		}
+	}
+}
+
+// drop closes every connection accepted so far.
+func (s *fakeJetStream) drop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.conns {
+		c.Close()
+	}
+	s.conns = nil
+}
+
+func (s *fakeJetStream) received() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Message(nil), s.messages...)
+}
+
+func TestNATSPublish(t *testing.T) {
+	s := newFakeJetStream(t)
+
+	p, err := NewNATS(s.url(""), "test")
+	require.NoError(t, err)
+	defer p.Close()
+
+	require.NoError(t, p.Publish(context.Background(), "blocks", []byte(`{"height":1}`)))
+	require.NoError(t, p.Publish(context.Background(), "blocks", []byte(`{"height":2}`)))
+
+	// the stream acknowledges a message after it is recorded
+	require.Equal(t, []Message{
+		{Subject: "blocks", Payload: []byte(`{"height":1}`)},
+		{Subject: "blocks", Payload: []byte(`{"height":2}`)},
+	}, s.received())
+}
+
+func TestNATSRejected(t *testing.T) {
+	s := newFakeJetStream(t)
+
+	p, err := NewNATS(s.url(""), "test")
+	require.NoError(t, err)
+	defer p.Close()
+
+	require.Error(t, p.Publish(context.Background(), "unknown", []byte(`{"height":1}`)))
+	require.Empty(t, s.received())
+}
+
+func TestNATSCredentials(t *testing.T) {
+	s := newFakeJetStream(t)
+
+	p, err := NewNATS(s.url("exporter:secret@"), "test")
+	require.NoError(t, err)
+	defer p.Close()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	require.Len(t, s.connects, 1)
+	require.Contains(t, s.connects[0], `"user":"exporter"`)
+	require.Contains(t, s.connects[0], `"pass":"secret"`)
+}
+
+func TestNATSReconnect(t *testing.T) {
+	s := newFakeJetStream(t)
+
+	p, err := NewNATS(s.url(""), "test")
+	require.NoError(t, err)
+	defer p.Close()
+
+	s.drop()
+	require.Eventually(t, func() bool {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		return p.Publish(ctx, "blocks", []byte(`{"height":1}`)) == nil
+	}, 10*time.Second, 100*time.Millisecond)
+	require.NotEmpty(t, s.received())
+}
+
+func TestNATSMaxPayload(t *testing.T) {
+	s := newFakeJetStream(t)
+
+	p, err := NewNATS(s.url(""), "test")
+	require.NoError(t, err)
+	defer p.Close()
+
+	require.Error(t, p.Publish(context.Background(), "blocks", make([]byte, 65)))
+	require.Empty(t, s.received())
+}
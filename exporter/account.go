@@ -13,7 +13,6 @@ import (
 	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
 
 	// mbl
-	mbltypes "github.com/cosmostation/mintscan-backend-library/types"
 	mdschema "github.com/cosmostation/mintscan-database/schema"
 
 	// tendermint
@@ -52,12 +51,12 @@ func (ex *Exporter) getAccounts(block *tmctypes.ResultBlock, txResps []*sdk.TxRe
 
 				// msgSend := m.(bank.MsgSend)
 
-				fromAcct, err := ex.Client.CliCtx.GetAccount(m.FromAddress)
+				fromAcct, err := ex.Client.GetAccount(m.FromAddress)
 				if err != nil {
 					return []mdschema.AccountCoin{}, err
 				}
 
-				toAcct, err := ex.Client.CliCtx.GetAccount(m.ToAddress)
+				toAcct, err := ex.Client.GetAccount(m.ToAddress)
 				if err != nil {
 					return []mdschema.AccountCoin{}, err
 				}
@@ -79,7 +78,7 @@ func (ex *Exporter) getAccounts(block *tmctypes.ResultBlock, txResps []*sdk.TxRe
 				var exportedAccts []sdkclient.Account
 
 				for _, input := range m.Inputs {
-					inputAcct, err := ex.Client.CliCtx.GetAccount(input.Address)
+					inputAcct, err := ex.Client.GetAccount(input.Address)
 					if err != nil {
 						return []mdschema.AccountCoin{}, err
 					}
@@ -88,7 +87,7 @@ func (ex *Exporter) getAccounts(block *tmctypes.ResultBlock, txResps []*sdk.TxRe
 				}
 
 				for _, output := range m.Outputs {
-					outputAcct, err := ex.Client.CliCtx.GetAccount(output.Address)
+					outputAcct, err := ex.Client.GetAccount(output.Address)
 					if err != nil {
 						return []mdschema.AccountCoin{}, err
 					}
@@ -106,17 +105,17 @@ func (ex *Exporter) getAccounts(block *tmctypes.ResultBlock, txResps []*sdk.TxRe
 
 				// msgDelegate := m.(staking.MsgDelegate)
 
-				delegatorAddr, err := ex.Client.CliCtx.GetAccount(m.DelegatorAddress)
+				delegatorAddr, err := ex.Client.GetAccount(m.DelegatorAddress)
 				if err != nil {
 					return []mdschema.AccountCoin{}, err
 				}
 
-				valAccAddr, err := mbltypes.ConvertAccAddrFromValAddr(m.DelegatorAddress)
+				valAccAddr, err := ex.Network.ConvertAccAddrFromValAddr(m.DelegatorAddress)
 				if err != nil {
 					return []mdschema.AccountCoin{}, err
 				}
 
-				valAddr, err := ex.Client.CliCtx.GetAccount(valAccAddr)
+				valAddr, err := ex.Client.GetAccount(valAccAddr)
 				if err != nil {
 					return []mdschema.AccountCoin{}, err
 				}
@@ -135,17 +134,17 @@ func (ex *Exporter) getAccounts(block *tmctypes.ResultBlock, txResps []*sdk.TxRe
 
 				// msgUndelegate := m.(staking.MsgUndelegate)
 
-				delegatorAddr, err := ex.Client.CliCtx.GetAccount(m.DelegatorAddress)
+				delegatorAddr, err := ex.Client.GetAccount(m.DelegatorAddress)
 				if err != nil {
 					return []mdschema.AccountCoin{}, err
 				}
 
-				valAccAddr, err := mbltypes.ConvertAccAddrFromValAddr(m.DelegatorAddress)
+				valAccAddr, err := ex.Network.ConvertAccAddrFromValAddr(m.DelegatorAddress)
 				if err != nil {
 					return []mdschema.AccountCoin{}, err
 				}
 
-				valAddr, err := ex.Client.CliCtx.GetAccount(valAccAddr)
+				valAddr, err := ex.Client.GetAccount(valAccAddr)
 				if err != nil {
 					return []mdschema.AccountCoin{}, err
 				}
@@ -164,27 +163,27 @@ func (ex *Exporter) getAccounts(block *tmctypes.ResultBlock, txResps []*sdk.TxRe
 
 				// msgBeginRedelegate := m.(staking.MsgBeginRedelegate)
 
-				delegatorAddr, err := ex.Client.CliCtx.GetAccount(m.DelegatorAddress)
+				delegatorAddr, err := ex.Client.GetAccount(m.DelegatorAddress)
 				if err != nil {
 					return []mdschema.AccountCoin{}, err
 				}
 
-				valSrcAccAddr, err := mbltypes.ConvertAccAddrFromValAddr(m.ValidatorSrcAddress)
+				valSrcAccAddr, err := ex.Network.ConvertAccAddrFromValAddr(m.ValidatorSrcAddress)
 				if err != nil {
 					return []mdschema.AccountCoin{}, err
 				}
 
-				valDstAccAddr, err := mbltypes.ConvertAccAddrFromValAddr(m.ValidatorDstAddress)
+				valDstAccAddr, err := ex.Network.ConvertAccAddrFromValAddr(m.ValidatorDstAddress)
 				if err != nil {
 					return []mdschema.AccountCoin{}, err
 				}
 
-				srcAddr, err := ex.Client.CliCtx.GetAccount(valSrcAccAddr)
+				srcAddr, err := ex.Client.GetAccount(valSrcAccAddr)
 				if err != nil {
 					return []mdschema.AccountCoin{}, err
 				}
 
-				dstAddr, err := ex.Client.CliCtx.GetAccount(valDstAccAddr)
+				dstAddr, err := ex.Client.GetAccount(valDstAccAddr)
 				if err != nil {
 					return []mdschema.AccountCoin{}, err
 				}
@@ -213,17 +212,17 @@ func (ex *Exporter) getAccountAllAssets(exportedAccts []sdkclient.Account, txHas
 		return []mdschema.AccountCoin{}, err
 	}
 
-	denom, err := ex.Client.GRPC.GetBondDenom(context.Background())
+	denom, err := ex.Client.GetBondDenom(context.Background())
 	if err != nil {
 		return []mdschema.AccountCoin{}, err
 	}
 
-	latestBlockHeight, err := ex.Client.RPC.GetLatestBlockHeight()
+	latestBlockHeight, err := ex.Client.GetLatestBlockHeight()
 	if err != nil {
 		return []mdschema.AccountCoin{}, err
 	}
 
-	block, err := ex.Client.RPC.GetBlock(latestBlockHeight)
+	block, err := ex.Client.GetBlock(latestBlockHeight)
 	if err != nil {
 		return []mdschema.AccountCoin{}, err
 	}
@@ -235,7 +234,7 @@ func (ex *Exporter) getAccountAllAssets(exportedAccts []sdkclient.Account, txHas
 
 			// acc := account.(*authtypes.BaseAccount)
 
-			available, rewards, commission, delegated, undelegated, err := ex.Client.GetBaseAccountTotalAsset(acc.GetAddress().String())
+			available, rewards, commission, delegated, undelegated, err := ex.Client.GetBaseAccountTotalAsset(ex.Network.AccAddress(acc.GetAddress()))
 			if err != nil {
 				return []mdschema.AccountCoin{}, err
 			}
@@ -276,7 +275,7 @@ func (ex *Exporter) getAccountAllAssets(exportedAccts []sdkclient.Account, txHas
 
 			// acc := account.(authtypes.ModuleAccountI)
 
-			available, rewards, commission, delegated, undelegated, err := ex.Client.GetBaseAccountTotalAsset(acc.GetAddress().String())
+			available, rewards, commission, delegated, undelegated, err := ex.Client.GetBaseAccountTotalAsset(ex.Network.AccAddress(acc.GetAddress()))
 			if err != nil {
 				return []mdschema.AccountCoin{}, err
 			}
@@ -292,7 +291,7 @@ func (ex *Exporter) getAccountAllAssets(exportedAccts []sdkclient.Account, txHas
 
 			acct := mdschema.AccountCoin{
 				// ChainID:           chainID,
-				Address: acc.GetAddress().String(),
+				Address: ex.Network.AccAddress(acc.GetAddress()),
 				// AccountNumber:     acc.GetAccountNumber(),
 				// AccountType:       types.ModuleAccount,
 				Denom:        denom,
@@ -317,7 +316,7 @@ func (ex *Exporter) getAccountAllAssets(exportedAccts []sdkclient.Account, txHas
 
 			// acc := account.(*authvestingtypes.PeriodicVestingAccount)
 
-			available, rewards, commission, delegated, undelegated, err := ex.Client.GetBaseAccountTotalAsset(acc.GetAddress().String())
+			available, rewards, commission, delegated, undelegated, err := ex.Client.GetBaseAccountTotalAsset(ex.Network.AccAddress(acc.GetAddress()))
 			if err != nil {
 				return []mdschema.AccountCoin{}, err
 			}
@@ -387,7 +386,7 @@ func (ex *Exporter) getAccountAllAssets(exportedAccts []sdkclient.Account, txHas
 
 			// acc := account.(*authvestingtypes.DelayedVestingAccount)
 
-			available, rewards, commission, delegated, undelegated, err := ex.Client.GetBaseAccountTotalAsset(acc.GetAddress().String())
+			available, rewards, commission, delegated, undelegated, err := ex.Client.GetBaseAccountTotalAsset(ex.Network.AccAddress(acc.GetAddress()))
 			if err != nil {
 				return []mdschema.AccountCoin{}, err
 			}
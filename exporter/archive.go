@@ -0,0 +1,273 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cosmostation/cosmostation-coreum/archive"
+	"github.com/cosmostation/cosmostation-coreum/custom"
+	"go.uber.org/zap"
+
+	mdschema "github.com/cosmostation/mintscan-database/schema"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+)
+
+// sources of archive mode
+const (
+	// ARCHIVE_SOURCE_RAWDB archives raw_block and raw_transaction rows stored by raw mode.
+	ARCHIVE_SOURCE_RAWDB = "rawdb"
+	// ARCHIVE_SOURCE_NODE archives blocks and transactions fetched from the node.
+	ARCHIVE_SOURCE_NODE = "node"
+)
+
+var (
+	// archivePath is the local directory of archive files.
+	archivePath = ""
+
+	// archiveSource is where archive mode reads raw data from.
+	archiveSource = ARCHIVE_SOURCE_RAWDB
+
+	// archiveRangeSize is the number of heights in an archive file.
+	archiveRangeSize = int64(10000)
+
+	// archiveBatchSize is the number of heights read from the raw database or refined from archive files at a time.
+	archiveBatchSize = 100
+)
+
+// SetArchiveOption sets the directory, the source and the number of heights per file of archive mode.
+func SetArchiveOption(path, source string, rangeSize int64) {
+	archivePath = path
+	switch source {
+	case ARCHIVE_SOURCE_RAWDB, ARCHIVE_SOURCE_NODE:
+		archiveSource = source
+	default:
+		zap.S().Infof("unknown archive source %s, will use %s", source, archiveSource)
+	}
+	if rangeSize > 0 {
+		archiveRangeSize = rangeSize
+	}
+	zap.S().Debugf("ArchivePath : %s, ArchiveSource : %s, ArchiveRangeSize : %d\n", archivePath, archiveSource, archiveRangeSize)
+}
+
+// Archive writes raw data of every height into archive files until ctx is canceled.
+// It continues after the last height of the manifest, and heights of an incomplete file are written again after a restart.
+func (ex *Exporter) Archive(ctx context.Context) error {
+	if archivePath == "" {
+		return fmt.Errorf("archive path is not set")
+	}
+
+	chainID := ex.Config.Chain.ChainID
+	w, err := archive.NewWriter(archivePath, chainID, archiveRangeSize)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	for {
+		if err := ex.archiveSync(ctx, w); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			zap.S().Errorf("error - archive: %s", err)
+			// heights of the incomplete file are written again from the last archived height
+			if err := w.Close(); err != nil {
+				zap.S().Errorf("failed to discard incomplete archive file: %s", err)
+			}
+		}
+		ex.beat()
+		if !sleep(ctx, 2*time.Second) {
+			return nil
+		}
+	}
+}
+
+// archiveSync writes the heights after the last written height up to the latest height of the source.
+func (ex *Exporter) archiveSync(ctx context.Context, w *archive.Writer) error {
+	chainID := ex.Config.Chain.ChainID
+
+	var begin, latest int64
+	var err error
+	switch archiveSource {
+	case ARCHIVE_SOURCE_RAWDB:
+		if begin, err = ex.RawDB.GetEarliestBlockHeight(chainID); err != nil {
+			return fmt.Errorf("failed to get earliest raw block height: %s", err)
+		}
+		if latest, err = ex.RawDB.GetChainLatestBlockHeight(chainID); latest == -1 {
+			return fmt.Errorf("failed to get latest raw block height: %s", err)
+		}
+	case ARCHIVE_SOURCE_NODE:
+		status, err := ex.Client.GetStatus()
+		if err != nil {
+			return fmt.Errorf("failed to get status: %s", err)
+		}
+		begin, latest = status.SyncInfo.EarliestBlockHeight, status.SyncInfo.LatestBlockHeight
+	}
+	if next := w.NextHeight(); next != 0 {
+		begin = next
+	}
+	if begin == 0 || begin > latest {
+		return nil
+	}
+
+	zap.S().Infof("archive heights from %d to %d", begin, latest)
+	if archiveSource == ARCHIVE_SOURCE_NODE {
+		return ex.archiveFromNode(ctx, w, begin, latest)
+	}
+	return ex.archiveFromRawDB(ctx, w, begin, latest)
+}
+
+// archiveFromRawDB writes the heights stored in the raw database. A missing height stops archiving,
+// since archive files must hold consecutive heights.
+func (ex *Exporter) archiveFromRawDB(ctx context.Context, w *archive.Writer, begin, end int64) error {
+	for from := begin; from <= end; from += int64(archiveBatchSize) {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		to := from + int64(archiveBatchSize) - 1
+		if to > end {
+			to = end
+		}
+		data, err := ex.RawDB.GetRawDataInRange(ex.Config.Chain.ChainID, from, to)
+		if err != nil {
+			return fmt.Errorf("failed to get raw data from %d to %d: %s", from, to, err)
+		}
+		for i := range data {
+			if err := w.Write(archiveEntry(&data[i])); err != nil {
+				return err
+			}
+		}
+		if next := w.NextHeight(); next <= to {
+			return fmt.Errorf("raw block at height %d is missing", next)
+		}
+		ex.beat()
+	}
+	return nil
+}
+
+// archiveFromNode writes the heights fetched from the node.
+func (ex *Exporter) archiveFromNode(ctx context.Context, w *archive.Writer, begin, end int64) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	fetch := func(height int64) (*fetchedBlock, error) {
+		return ex.fetchBlock(ctx, height, false)
+	}
+
+	for fb := range fetchBlocks(ctx.Done(), begin, end, fetch) {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if fb.err != nil {
+			return fmt.Errorf("failed to get block and txs at height %d : %s", fb.height, fb.err)
+		}
+
+		rawData, err := ex.getRawData(fb.block, fb.txs)
+		if err != nil {
+			return err
+		}
+		if err := w.Write(archiveEntry(rawData)); err != nil {
+			return err
+		}
+		ex.beat()
+	}
+	return nil
+}
+
+// archiveEntry returns the archive entry of the raw data of a height.
+func archiveEntry(raw *mdschema.RawData) archive.Entry {
+	e := archive.Entry{
+		ChainID:   raw.Block.ChainID,
+		Height:    raw.Block.Height,
+		BlockHash: raw.Block.BlockHash,
+		Block:     raw.Block.Chunk,
+		Txs:       make([]archive.Tx, len(raw.Transactions)),
+	}
+	for i, tx := range raw.Transactions {
+		e.Txs[i] = archive.Tx{Hash: tx.TxHash, Chunk: tx.Chunk}
+	}
+	return e
+}
+
+// RefineFromArchive refines blocks and transactions from archive files instead of the raw database,
+// and then keeps refining new heights from the node as Refine does.
+func (ex *Exporter) RefineFromArchive(ctx context.Context) error {
+	chainID := ex.Config.Chain.ChainID
+	chainInfoID := ex.ChainIDMap[chainID]
+
+	r, err := archive.OpenReader(archivePath, chainID)
+	if err != nil {
+		return err
+	}
+
+	blockHeight, err := ex.DB.GetLatestBlockHeight(chainInfoID)
+	if blockHeight == -1 {
+		return fmt.Errorf("failed to get latest block height: %s", err)
+	}
+	lastTx, err := ex.DB.GetLatestTransaction(chainInfoID)
+	if err != nil {
+		return fmt.Errorf("failed to get latest transaction: %s", err)
+	}
+	txHeight := lastTx.Height
+
+	// heights are refined as a whole, so both cursors are heights
+	from := blockHeight
+	if txHeight < from {
+		from = txHeight
+	}
+
+	zap.S().Infof("will be refine archive of %s from height %d to %d", chainID, from+1, r.Manifest().LastHeight())
+	err = r.Replay(from+1, archiveBatchSize, func(entries []archive.Entry) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		var blocks []mdschema.RawBlock
+		var txs []*sdktypes.TxResponse
+		for _, e := range entries {
+			if e.Height > blockHeight {
+				blocks = append(blocks, mdschema.RawBlock{
+					ChainID:   e.ChainID,
+					Height:    e.Height,
+					BlockHash: e.BlockHash,
+					NumTxs:    int64(len(e.Txs)),
+					Chunk:     e.Block,
+				})
+			}
+			if e.Height <= txHeight {
+				continue
+			}
+			for _, t := range e.Txs {
+				tx := new(sdktypes.TxResponse)
+				if err := custom.CodecAt(e.ChainID, e.Height).UnmarshalJSON(t.Chunk, tx); err != nil {
+					return fmt.Errorf("failed to unmarshal tx %s: %s", t.Hash, err)
+				}
+				txs = append(txs, tx)
+			}
+		}
+
+		if len(blocks) > 0 {
+			if err := ex.refineRawBlocks(blocks); err != nil {
+				return err
+			}
+		}
+		if len(txs) > 0 {
+			if err := ex.refineRawTransactions(chainID, txs); err != nil {
+				return err
+			}
+		}
+		ex.beat()
+		zap.S().Infof("refined archive to height %d", entries[len(entries)-1].Height)
+		return nil
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return err
+	}
+
+	return ex.refineRealTime(ctx)
+}
@@ -0,0 +1,219 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cosmostation/cosmostation-coreum/db"
+	"go.uber.org/zap"
+)
+
+var (
+	// auditInterval is the interval between audits of stored heights. Audit is disabled when it is 0.
+	auditInterval = time.Duration(0)
+
+	// maxRepairsPerAudit bounds the number of heights repaired in a single audit, the rest is repaired by the next audit.
+	maxRepairsPerAudit = 1000
+
+	// auditRangeSize bounds the range of heights audited at a time, the rest is audited by the next audit.
+	auditRangeSize = int64(100000)
+)
+
+// AuditReport summarizes what a single audit found and repaired.
+type AuditReport struct {
+	MissingBlocks    int
+	MissingRawBlocks int
+	NumTxsMismatches int
+	Repaired         []int64
+	Failed           []int64
+}
+
+// SetAuditInterval sets the interval of the background audit of stored heights.
+func SetAuditInterval(interval time.Duration) {
+	auditInterval = interval
+	zap.S().Debugf("AuditInterval : %s\n", auditInterval)
+}
+
+// runAuditor audits stored heights every auditInterval until ctx is canceled.
+func (ex *Exporter) runAuditor(ctx context.Context, op int) {
+	if auditInterval <= 0 {
+		return
+	}
+
+	for sleep(ctx, auditInterval) {
+		zap.S().Info("start - audit stored heights")
+		report, err := ex.audit(ctx, op)
+		if err != nil {
+			zap.S().Errorf("error - audit stored heights: %s", err)
+			continue
+		}
+		zap.S().Infof("finish - audit stored heights, missing blocks : %d, missing raw blocks : %d, num_txs mismatches : %d, repaired : %v, failed : %v",
+			report.MissingBlocks, report.MissingRawBlocks, report.NumTxsMismatches, report.Repaired, report.Failed)
+	}
+}
+
+// audit looks for heights missing in block or raw_block tables and for blocks whose num_txs differs
+// from the number of stored transactions, and exports those heights again. Each table is audited from the height
+// its audit cursor reached, up to auditRangeSize heights at a time, and the cursor stops below the lowest height
+// which is not repaired, so that it is audited again.
+func (ex *Exporter) audit(ctx context.Context, op int) (report AuditReport, err error) {
+	chainInfoID := ex.ChainIDMap[ex.Config.Chain.ChainID]
+	targets := make(map[int64]int)
+	var basicTo, rawTo int64
+
+	if op == BASIC_MODE {
+		earliest, err := ex.DB.GetEarliestBlockHeight(chainInfoID)
+		if err != nil {
+			return report, fmt.Errorf("failed to get earliest block height: %s", err)
+		}
+		latest, err := ex.DB.GetLatestBlockHeight(chainInfoID)
+		if latest == -1 {
+			return report, fmt.Errorf("failed to get latest block height: %s", err)
+		}
+		cursor, err := ex.DB.GetIndexCursor(chainInfoID, db.AuditCursor)
+		if err != nil {
+			return report, fmt.Errorf("failed to get index cursor: %s", err)
+		}
+
+		if from, to, ok := auditWindow(cursor, earliest, latest); ok {
+			basicTo = to
+
+			gaps, err := ex.DB.GetBlockHeightGaps(chainInfoID, from, to)
+			if err != nil {
+				return report, fmt.Errorf("failed to get block height gaps: %s", err)
+			}
+			report.MissingBlocks = addGapTargets(targets, gaps, replaceBasic)
+
+			mismatches, err := ex.DB.GetBlockNumTxsMismatches(chainInfoID, from, to)
+			if err != nil {
+				return report, fmt.Errorf("failed to get num_txs mismatches: %s", err)
+			}
+			for _, h := range mismatches {
+				targets[h] |= replaceBasic
+			}
+			report.NumTxsMismatches = len(mismatches)
+		}
+	}
+
+	if op == BASIC_MODE || op == RAW_MODE {
+		chainID := ex.Config.Chain.ChainID
+
+		earliest, err := ex.RawDB.GetEarliestBlockHeight(chainID)
+		if err != nil {
+			return report, fmt.Errorf("failed to get earliest raw block height: %s", err)
+		}
+		latest, err := ex.RawDB.GetChainLatestBlockHeight(chainID)
+		if latest == -1 {
+			return report, fmt.Errorf("failed to get latest raw block height: %s", err)
+		}
+		cursor, err := ex.DB.GetIndexCursor(chainInfoID, db.RawAuditCursor)
+		if err != nil {
+			return report, fmt.Errorf("failed to get index cursor: %s", err)
+		}
+
+		if from, to, ok := auditWindow(cursor, earliest, latest); ok {
+			rawTo = to
+
+			gaps, err := ex.RawDB.GetBlockHeightGaps(chainID, from, to)
+			if err != nil {
+				return report, fmt.Errorf("failed to get raw block height gaps: %s", err)
+			}
+			report.MissingRawBlocks = addGapTargets(targets, gaps, replaceRaw)
+		}
+	}
+
+	repaired := make(map[int64]bool)
+	for _, h := range selectRepairs(targets) {
+		if ctx.Err() != nil {
+			return report, ctx.Err()
+		}
+
+		if err := ex.repairHeight(ctx, h, targets[h]); err != nil {
+			zap.S().Errorf("failed to repair height %d : %s", h, err)
+			report.Failed = append(report.Failed, h)
+			continue
+		}
+		report.Repaired = append(report.Repaired, h)
+		repaired[h] = true
+	}
+
+	if basicTo > 0 {
+		if err := ex.DB.SetIndexCursor(chainInfoID, db.AuditCursor, nextAuditCursor(basicTo, targets, replaceBasic, repaired)); err != nil {
+			return report, fmt.Errorf("failed to set index cursor: %s", err)
+		}
+	}
+	if rawTo > 0 {
+		if err := ex.DB.SetIndexCursor(chainInfoID, db.RawAuditCursor, nextAuditCursor(rawTo, targets, replaceRaw, repaired)); err != nil {
+			return report, fmt.Errorf("failed to set index cursor: %s", err)
+		}
+	}
+
+	return report, nil
+}
+
+// auditWindow returns the heights to audit after the cursor, the last height audited, up to auditRangeSize heights.
+// The window starts at the cursor, so that a gap right after it is found. ok is false when no height is left to audit.
+func auditWindow(cursor, earliest, latest int64) (from, to int64, ok bool) {
+	if earliest == 0 || cursor >= latest {
+		return 0, 0, false
+	}
+
+	from = cursor
+	if from < earliest {
+		from = earliest
+	}
+	to = latest
+	if to > from+auditRangeSize {
+		to = from + auditRangeSize
+	}
+	return from, to, true
+}
+
+// addGapTargets adds every height of the gaps to the targets with the target and returns the number of heights added.
+func addGapTargets(targets map[int64]int, gaps []db.HeightRange, target int) int {
+	missing := 0
+	for _, g := range gaps {
+		for h := g.From; h <= g.To; h++ {
+			targets[h] |= target
+		}
+		missing += int(g.To - g.From + 1)
+	}
+	return missing
+}
+
+// selectRepairs returns the lowest heights of the targets in ascending order, up to maxRepairsPerAudit heights.
+func selectRepairs(targets map[int64]int) []int64 {
+	heights := make([]int64, 0, len(targets))
+	for h := range targets {
+		heights = append(heights, h)
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+	if len(heights) > maxRepairsPerAudit {
+		heights = heights[:maxRepairsPerAudit]
+	}
+	return heights
+}
+
+// nextAuditCursor returns the cursor of the audit of the target after the heights up to the height to were audited.
+// It stops right below the lowest height of the target which is not repaired.
+func nextAuditCursor(to int64, targets map[int64]int, target int, repaired map[int64]bool) int64 {
+	next := to
+	for h, t := range targets {
+		if t&target != 0 && !repaired[h] && h-1 < next {
+			next = h - 1
+		}
+	}
+	return next
+}
+
+// repairHeight exports the height again through the same path as backfill.
+func (ex *Exporter) repairHeight(ctx context.Context, height int64, target int) error {
+	fb, err := ex.fetchBlock(ctx, height, indexBlockEvents && target&replaceBasic != 0)
+	if err != nil {
+		return fmt.Errorf("failed to get block and txs : %s", err)
+	}
+
+	return ex.replaceHeight(fb, target)
+}
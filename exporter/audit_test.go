@@ -0,0 +1,49 @@
+package exporter
+
+import (
+	"testing"
+
+	"github.com/cosmostation/cosmostation-coreum/db"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditWindow(t *testing.T) {
+	// the first audit starts at the earliest height
+	from, to, ok := auditWindow(0, 100, 200)
+	require.True(t, ok)
+	require.Equal(t, int64(100), from)
+	require.Equal(t, int64(200), to)
+
+	// later audits start at the cursor and are bounded by auditRangeSize
+	from, to, ok = auditWindow(150, 100, 150+2*auditRangeSize)
+	require.True(t, ok)
+	require.Equal(t, int64(150), from)
+	require.Equal(t, 150+auditRangeSize, to)
+
+	_, _, ok = auditWindow(200, 100, 200)
+	require.False(t, ok)
+	_, _, ok = auditWindow(0, 0, 0)
+	require.False(t, ok)
+}
+
+func TestAuditTargets(t *testing.T) {
+	defer func(n int) { maxRepairsPerAudit = n }(maxRepairsPerAudit)
+	maxRepairsPerAudit = 3
+
+	targets := make(map[int64]int)
+	require.Equal(t, 3, addGapTargets(targets, []db.HeightRange{{From: 12, To: 13}, {From: 20, To: 20}}, replaceBasic))
+	require.Equal(t, 2, addGapTargets(targets, []db.HeightRange{{From: 13, To: 14}}, replaceRaw))
+	targets[11] |= replaceBasic // num_txs mismatch
+
+	require.Equal(t, map[int64]int{11: replaceBasic, 12: replaceBasic, 13: replaceBasic | replaceRaw, 14: replaceRaw, 20: replaceBasic}, targets)
+	require.Equal(t, []int64{11, 12, 13}, selectRepairs(targets))
+
+	// heights left for the next audit and failed heights hold the cursors below them
+	repaired := map[int64]bool{11: true, 13: true}
+	require.Equal(t, int64(11), nextAuditCursor(30, targets, replaceBasic, repaired))
+	require.Equal(t, int64(13), nextAuditCursor(30, targets, replaceRaw, repaired))
+
+	repaired = map[int64]bool{11: true, 12: true, 13: true, 14: true, 20: true}
+	require.Equal(t, int64(30), nextAuditCursor(30, targets, replaceBasic, repaired))
+	require.Equal(t, int64(30), nextAuditCursor(30, targets, replaceRaw, repaired))
+}
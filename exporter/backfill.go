@@ -0,0 +1,103 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cosmostation/cosmostation-coreum/db"
+	"github.com/cosmostation/cosmostation-coreum/sink"
+	"go.uber.org/zap"
+)
+
+// Backfill exports blocks in the closed range [from, to] again and replaces the data stored at those heights.
+// Heights above the latest height stored in database belong to the live sync and can not be backfilled.
+func (ex *Exporter) Backfill(ctx context.Context, from, to int64) error {
+	if from <= 0 || to < from {
+		return fmt.Errorf("invalid backfill range from %d to %d", from, to)
+	}
+	if !ex.storesInDatabase() {
+		return fmt.Errorf("backfill replaces stored heights in database and can not run with a sink other than %s", sink.POSTGRES_SINK)
+	}
+
+	dbHeight, err := ex.DB.GetLatestBlockHeight(ex.ChainIDMap[ex.Config.Chain.ChainID])
+	if dbHeight == -1 {
+		return fmt.Errorf("unexpected error in database: %s", err)
+	}
+	if to > dbHeight {
+		return fmt.Errorf("backfill range must end at or below the latest stored height %d, got %d", dbHeight, to)
+	}
+
+	zap.S().Infof("start backfill from %d to %d", from, to)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	fetch := func(height int64) (*fetchedBlock, error) {
+		return ex.fetchBlock(ctx, height, indexBlockEvents)
+	}
+
+	for fb := range fetchBlocks(ctx.Done(), from, to, fetch) {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if fb.err != nil {
+			return fmt.Errorf("failed to get block and txs at height %d : %s", fb.height, fb.err)
+		}
+
+		if err := ex.backfillHeight(fb); err != nil {
+			return fmt.Errorf("failed to backfill height %d : %s", fb.height, err)
+		}
+		ex.beat()
+		zap.S().Infof("backfilled block %d/%d", fb.height, to)
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	zap.S().Infof("finish backfill from %d to %d", from, to)
+	return nil
+}
+
+const (
+	// replaceBasic replaces the data stored by basic mode.
+	replaceBasic = 1 << iota
+	// replaceRaw replaces the data stored by raw mode.
+	replaceRaw
+)
+
+// backfillHeight replaces the basic and raw data stored at the height of the block.
+// Push notifications are not sent again for backfilled heights.
+func (ex *Exporter) backfillHeight(fb *fetchedBlock) error {
+	return ex.replaceHeight(fb, replaceBasic|replaceRaw)
+}
+
+// replaceHeight replaces the data selected by target at the height of the block.
+// The events of block results are replaced with the basic data when fb has block results.
+func (ex *Exporter) replaceHeight(fb *fetchedBlock, target int) error {
+	block, txs := fb.block, fb.txs
+
+	if target&replaceBasic != 0 {
+		basic, err := ex.getBasicData(block, txs)
+		if err != nil {
+			return err
+		}
+		e := &db.ExportedData{BasicData: basic, ChainID: block.Block.ChainID}
+		e.Events, e.Attributes = getBlockEvents(block.Block.ChainID, block.Block.Txs, fb.results)
+		if err := ex.DB.ReplaceExportedData(e); err != nil {
+			return fmt.Errorf("failed to replace basic data: %s", err)
+		}
+	}
+
+	if target&replaceRaw != 0 {
+		rawData, err := ex.getRawData(block, txs)
+		if err != nil {
+			return err
+		}
+		if err := ex.RawDB.ReplaceExportedData(rawData); err != nil {
+			return fmt.Errorf("failed to replace raw data: %s", err)
+		}
+	}
+
+	return nil
+}
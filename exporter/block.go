@@ -3,6 +3,8 @@ package exporter
 import (
 	"fmt"
 
+	"github.com/cosmostation/cosmostation-coreum/db"
+
 	tmjson "github.com/cometbft/cometbft/libs/json"
 	tmctypes "github.com/cometbft/cometbft/rpc/core/types"
 	mdschema "github.com/cosmostation/mintscan-database/schema"
@@ -20,7 +22,7 @@ func (ex *Exporter) getBlock(block *tmctypes.ResultBlock) (*mdschema.Block, erro
 			ns++
 		}
 	}
-	if block.Block.Height == 1 || block.Block.Height == initialHeight {
+	if block.Block.Height == 1 || block.Block.Height == ex.InitialHeight {
 		ph = "genesis"
 	}
 	b := &mdschema.Block{
@@ -48,7 +50,11 @@ func (ex *Exporter) getBlockFromDB(rawBlocks []mdschema.RawBlock) (blocks []mdsc
 
 	for i := range rawBlocks {
 		var block tmctypes.ResultBlock
-		err := tmjson.Unmarshal(rawBlocks[i].Chunk, &block)
+		chunk, err := db.DecompressChunk(rawBlocks[i].Chunk)
+		if err != nil {
+			return blocks, err
+		}
+		err = tmjson.Unmarshal(chunk, &block)
 		if err != nil {
 			return blocks, fmt.Errorf("failed to marshal block : %s", err)
 		}
@@ -59,7 +65,7 @@ func (ex *Exporter) getBlockFromDB(rawBlocks []mdschema.RawBlock) (blocks []mdsc
 				ns++
 			}
 		}
-		if block.Block.Height == 1 || block.Block.Height == initialHeight {
+		if block.Block.Height == 1 || block.Block.Height == ex.InitialHeight {
 			ph = "genesis"
 		}
 		b := mdschema.Block{
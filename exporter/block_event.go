@@ -0,0 +1,88 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cosmostation/cosmostation-coreum/db"
+	"github.com/cosmostation/cosmostation-coreum/metrics"
+	"go.uber.org/zap"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	tmctypes "github.com/cometbft/cometbft/rpc/core/types"
+	tmtypes "github.com/cometbft/cometbft/types"
+)
+
+// indexBlockEvents enables storing the events of block results in basic mode.
+var indexBlockEvents = true
+
+// SetBlockEventOption sets whether basic mode stores the events of block results.
+func SetBlockEventOption(enabled bool) {
+	indexBlockEvents = enabled
+	zap.S().Debugf("IndexBlockEvents : %t\n", indexBlockEvents)
+}
+
+// getBlockResults queries the block results at the height.
+func (ex *Exporter) getBlockResults(ctx context.Context, height int64) (*tmctypes.ResultBlockResults, error) {
+	results, err := ex.Client.BlockResults(ctx, &height)
+	if err != nil {
+		metrics.NodeRPCErrors.WithLabelValues("BlockResults").Inc()
+		return nil, fmt.Errorf("failed to query block results at %d : %s", height, err)
+	}
+	return results, nil
+}
+
+// getBlockEvents normalizes the begin-block, tx and end-block events of block results into rows.
+// Tendermint of this chain has no finalize-block phase, so every event of a block belongs to one of these.
+// Events of tx phase carry the hash of their transaction in txs, the transactions of the block.
+// It returns nil when results is nil, so that the events stored at the height are kept.
+func getBlockEvents(chainID string, txs tmtypes.Txs, results *tmctypes.ResultBlockResults) ([]db.BlockEvent, []db.BlockEventAttribute) {
+	if results == nil {
+		return nil, nil
+	}
+	events := make([]db.BlockEvent, 0)
+	attrs := make([]db.BlockEventAttribute, 0)
+
+	add := func(phase string, txIndex int, txHash string, list []abci.Event) {
+		for i, e := range list {
+			events = append(events, db.BlockEvent{
+				ChainID:    chainID,
+				Height:     results.Height,
+				Phase:      phase,
+				TxIndex:    txIndex,
+				EventIndex: i,
+				Type:       e.Type,
+				TxHash:     txHash,
+			})
+			for j, a := range e.Attributes {
+				attrs = append(attrs, db.BlockEventAttribute{
+					ChainID:    chainID,
+					Height:     results.Height,
+					Phase:      phase,
+					TxIndex:    txIndex,
+					EventIndex: i,
+					Position:   j,
+					Type:       e.Type,
+					Key:        a.Key,
+					Value:      a.Value,
+					TxHash:     txHash,
+				})
+			}
+		}
+	}
+
+	add(db.PHASE_BEGIN_BLOCK, -1, "", results.BeginBlockEvents)
+	for i, tx := range results.TxsResults {
+		if tx == nil {
+			continue
+		}
+		var txHash string
+		if i < len(txs) {
+			txHash = fmt.Sprintf("%X", txs[i].Hash())
+		}
+		add(db.PHASE_TX, i, txHash, tx.Events)
+	}
+	add(db.PHASE_END_BLOCK, -1, "", results.EndBlockEvents)
+
+	return events, attrs
+}
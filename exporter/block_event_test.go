@@ -0,0 +1,49 @@
+package exporter
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cosmostation/cosmostation-coreum/db"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/cometbft/cometbft/abci/types"
+	tmctypes "github.com/cometbft/cometbft/rpc/core/types"
+	tmtypes "github.com/cometbft/cometbft/types"
+)
+
+func TestGetBlockEvents(t *testing.T) {
+	results := &tmctypes.ResultBlockResults{
+		Height: 10,
+		BeginBlockEvents: []abci.Event{
+			{Type: "mint", Attributes: []abci.EventAttribute{{Key: "amount", Value: "100"}}},
+		},
+		TxsResults: []*abci.ResponseDeliverTx{
+			{Events: []abci.Event{
+				{Type: "message", Attributes: []abci.EventAttribute{{Key: "action", Value: "send"}}},
+				{Type: "transfer", Attributes: []abci.EventAttribute{{Key: "recipient", Value: "a"}, {Key: "amount", Value: "1"}}},
+			}},
+		},
+		EndBlockEvents: []abci.Event{
+			{Type: "complete_unbonding"},
+		},
+	}
+
+	txs := tmtypes.Txs{tmtypes.Tx("tx")}
+	txHash := fmt.Sprintf("%X", txs[0].Hash())
+
+	events, attrs := getBlockEvents("test-1", txs, results)
+
+	require.Len(t, events, 4)
+	require.Equal(t, db.BlockEvent{ChainID: "test-1", Height: 10, Phase: db.PHASE_BEGIN_BLOCK, TxIndex: -1, EventIndex: 0, Type: "mint"}, events[0])
+	require.Equal(t, db.BlockEvent{ChainID: "test-1", Height: 10, Phase: db.PHASE_TX, TxIndex: 0, EventIndex: 1, Type: "transfer", TxHash: txHash}, events[2])
+	require.Equal(t, db.BlockEvent{ChainID: "test-1", Height: 10, Phase: db.PHASE_END_BLOCK, TxIndex: -1, EventIndex: 0, Type: "complete_unbonding"}, events[3])
+
+	require.Len(t, attrs, 4)
+	require.Equal(t, db.BlockEventAttribute{ChainID: "test-1", Height: 10, Phase: db.PHASE_TX, TxIndex: 0, EventIndex: 1, Position: 1, Type: "transfer", Key: "amount", Value: "1", TxHash: txHash}, attrs[3])
+
+	// without block results the stored events are kept
+	events, attrs = getBlockEvents("test-1", txs, nil)
+	require.Nil(t, events)
+	require.Nil(t, attrs)
+}
@@ -1,6 +1,7 @@
 package exporter
 
 import (
+	"flag"
 	"os"
 	"testing"
 
@@ -12,8 +13,19 @@ var (
 )
 
 func TestMain(m *testing.M) {
-	chainEx := app.NewApp("chain-exporter")
-	ex = NewExporter(chainEx)
+	flag.Parse()
+	// the fixture needs a node and databases, so -short runs only the tests which stub them
+	if !testing.Short() {
+		chainEx := app.NewApp("chain-exporter")
+		ex = NewExporter(chainEx)
+	}
 
 	os.Exit(m.Run())
 }
+
+// requireFixture skips a test of the ex fixture when it is not set up by -short.
+func requireFixture(t *testing.T) {
+	if ex == nil {
+		t.Skip("needs a node and databases")
+	}
+}
@@ -0,0 +1,108 @@
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cosmostation/cosmostation-coreum/custom"
+	"github.com/cosmostation/cosmostation-coreum/event"
+	"go.uber.org/zap"
+
+	mdschema "github.com/cosmostation/mintscan-database/schema"
+
+	tmctypes "github.com/cometbft/cometbft/rpc/core/types"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+)
+
+// newBlockEvent builds the event published after the basic data of the block is committed.
+func newBlockEvent(block *tmctypes.ResultBlock, txs []*sdktypes.TxResponse, basic *mdschema.BasicData) *event.BlockEvent {
+	e := &event.BlockEvent{
+		Version:     event.BlockEventVersion,
+		ChainID:     block.Block.ChainID,
+		Height:      block.Block.Height,
+		Hash:        block.BlockID.Hash.String(),
+		ParentHash:  block.Block.LastBlockID.Hash.String(),
+		Proposer:    block.Block.ProposerAddress.String(),
+		NumTxs:      len(block.Block.Txs),
+		Timestamp:   block.Block.Time,
+		Txs:         make([]event.TxSummary, 0, len(txs)),
+		TMAs:        basic.TMAs,
+		Proposals:   basic.Proposals,
+		Deposits:    basic.Deposits,
+		Votes:       basic.Votes,
+		PowerEvents: basic.ValidatorsPowerEventHistory,
+	}
+
+	for _, tx := range txs {
+		s := event.TxSummary{
+			Hash:      tx.TxHash,
+			Code:      tx.Code,
+			Codespace: tx.Codespace,
+			GasWanted: tx.GasWanted,
+			GasUsed:   tx.GasUsed,
+			Messages:  make([]string, 0),
+		}
+		if t := tx.GetTx(); t != nil {
+			for _, msg := range t.GetMsgs() {
+				s.Messages = append(s.Messages, sdktypes.MsgTypeURL(msg))
+			}
+			if m, ok := t.(sdktypes.TxWithMemo); ok {
+				s.Memo = m.GetMemo()
+			}
+		}
+		e.Txs = append(e.Txs, s)
+	}
+
+	return e
+}
+
+// publishBlock publishes the block event of a committed height and moves the cursor of the subject.
+// A crash between the two publishes the height again, so consumers see every height at least once.
+func (ex *Exporter) publishBlock(ctx context.Context, block *tmctypes.ResultBlock, txs []*sdktypes.TxResponse, basic *mdschema.BasicData) error {
+	payload, err := json.Marshal(newBlockEvent(block, txs, basic))
+	if err != nil {
+		return fmt.Errorf("failed to marshal block event: %s", err)
+	}
+
+	if err := ex.Publisher.Publish(ctx, ex.EventSubject, payload); err != nil {
+		return fmt.Errorf("failed to publish block event at %d : %s", block.Block.Height, err)
+	}
+
+	if err := ex.DB.SetEventCursor(ex.Config.Chain.ChainID, ex.EventSubject, block.Block.Height); err != nil {
+		return fmt.Errorf("failed to save event cursor at %d : %s", block.Block.Height, err)
+	}
+	return nil
+}
+
+// publishPending publishes the events of heights which were committed up to dbHeight but not yet published,
+// for example because the broker was unavailable. The cursor starts at dbHeight when the subject is new.
+func (ex *Exporter) publishPending(ctx context.Context, dbHeight int64) error {
+	cursor, err := ex.DB.GetEventCursor(ex.Config.Chain.ChainID, ex.EventSubject)
+	if err != nil {
+		return fmt.Errorf("failed to get event cursor: %s", err)
+	}
+	if cursor == 0 {
+		return ex.DB.SetEventCursor(ex.Config.Chain.ChainID, ex.EventSubject, dbHeight)
+	}
+
+	for h := cursor + 1; h <= dbHeight; h++ {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		block, txs, err := ex.Client.GetBlockAndTxsFromNode(custom.EncodingConfigAt(ex.Config.Chain.ChainID, h), h)
+		if err != nil {
+			return fmt.Errorf("failed to get block and txs at height %d : %s", h, err)
+		}
+		basic, err := ex.getBasicData(block, txs)
+		if err != nil {
+			return err
+		}
+		if err := ex.publishBlock(ctx, block, txs, basic); err != nil {
+			return err
+		}
+		zap.S().Infof("published pending block event %d/%d", h, dbHeight)
+	}
+	return nil
+}
@@ -1,13 +1,20 @@
 package exporter
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/cosmostation/cosmostation-coreum/app"
 	"github.com/cosmostation/cosmostation-coreum/custom"
+	"github.com/cosmostation/cosmostation-coreum/db"
+	"github.com/cosmostation/cosmostation-coreum/event"
+	"github.com/cosmostation/cosmostation-coreum/metrics"
+	"github.com/cosmostation/cosmostation-coreum/sink"
 	"go.uber.org/zap"
 
 	// mbl
@@ -25,19 +32,62 @@ var (
 	// Commit is commit hash of this project.
 	Commit = ""
 
-	controler     = make(chan struct{}, 60)
-	wg            = new(sync.WaitGroup)
+	// initialHeight is the initial height of new exporters.
 	initialHeight = int64(0)
 )
 
 // Exporter is
 type Exporter struct {
 	*app.App
+
+	// lastBlock caches the last committed block to verify the parent hash of the next block.
+	lastBlock blockRef
+
+	// Sink stores the data extracted for each height. It writes to the databases of the app by default.
+	Sink sink.Sink
+
+	// Publisher publishes a block event to EventSubject after each height committed by basic mode. It is disabled when nil.
+	Publisher    event.Publisher
+	EventSubject string
+
+	// WSEndpoint is the CometBFT RPC endpoint whose websocket reports new blocks of the chain. Polling is used when it is empty.
+	WSEndpoint string
+
+	// AggregateFees runs the fee maker in basic mode. The fee pointer is shared by every chain of the database,
+	// so only one exporter of a process aggregates fees.
+	AggregateFees bool
+
+	// InitialHeight is the first height synced when no height is stored yet, 0 to sync from the first height of the chain.
+	InitialHeight int64
+
+	// propList is the ids of live proposals which are updated until they end. It is guarded by muProp.
+	propList map[uint64]struct{}
+	muProp   sync.RWMutex
+
+	// gasPrices is the gas price window of the oracle. The oracle is disabled when it is nil.
+	gasPrices *gasPriceWindow
+
+	// heartbeat is the unix time in nanoseconds when sync last made progress.
+	heartbeat atomic.Int64
+
+	// fetch queries the heights synced by syncTo. fetchBlock is used when it is nil.
+	fetch func(ctx context.Context, height int64, withResults bool) (*fetchedBlock, error)
 }
 
 // NewExporter returns new Exporter instance
 func NewExporter(a *app.App) *Exporter {
-	return &Exporter{a}
+	ex := &Exporter{
+		App:           a,
+		Sink:          sink.NewPostgres(a.DB, a.RawDB),
+		WSEndpoint:    wsEndpoint,
+		AggregateFees: aggregateFees,
+		InitialHeight: initialHeight,
+		propList:      make(map[uint64]struct{}),
+	}
+	if gasPriceBlocks > 0 {
+		ex.gasPrices = newGasPriceWindow(gasPriceBlocks)
+	}
+	return ex
 }
 
 // preProcess 는 실제 프로세스 수행 전, 필요한 설정 환경 등을 동적으로 설정
@@ -46,38 +96,90 @@ func SetInitialHeight(height int64) {
 	zap.S().Debugf("InitialHeight : %d\n", initialHeight)
 }
 
-// Start starts to synchronize blockchain data
-func (ex *Exporter) Start(op int) {
+// Start starts to synchronize blockchain data.
+// It blocks until ctx is canceled and every goroutine it started has returned.
+// The height being synced when ctx is canceled is committed before Start returns.
+func (ex *Exporter) Start(ctx context.Context, op int) {
 	zap.S().Info("Starting Chain Exporter...")
 	zap.S().Infof("Version: %s | Commit: %s", Version, Commit)
-	zap.S().Infof("Schema Info : %s, %s\n", mdschema.GetCommonSchema(), mdschema.GetChainSchema())
+	zap.S().Infof("Schema Info : %s, %s\n", ex.Config.DB.CommonSchema, ex.Config.DB.ChainSchema)
 
-	tick10Sec := time.NewTicker(time.Second * 10)
-	tick20Min := time.NewTicker(time.Minute * 20)
-
-	done := make(chan struct{})
-	defer close(done)
+	routines := new(sync.WaitGroup)
 
+	routines.Add(1)
 	go func() {
-		for {
-			zap.S().Info("start - sync blockchain")
-			err := ex.sync(op)
-			if err != nil {
-				zap.S().Infof("error - sync blockchain: %s\n", err)
-			}
-			zap.S().Info("finish - sync blockchain")
+		defer routines.Done()
 
-			time.Sleep(time.Second)
+		var err error
+		if ex.WSEndpoint != "" {
+			err = ex.follow(ctx, op)
+		} else {
+			err = ex.poll(ctx, op, 0)
+		}
+		if errors.Is(err, errChainHalted) {
+			zap.S().Errorf("stop - sync blockchain: %s\n", err)
 		}
 	}()
-	// go ex.runFeeMaker()
 	// app init 시 최초 전체 프로포절 업데이트
 	ex.saveAllProposals()
-	go ex.watchLiveProposals()
-	go ex.updateProposals()
+
+	routines.Add(2)
+	go func() {
+		defer routines.Done()
+		ex.watchLiveProposals(ctx)
+	}()
+	go func() {
+		defer routines.Done()
+		ex.updateProposals(ctx)
+	}()
+
+	// the auditor, the outbox dispatcher and the indexers read what sync stored in database
+	if ex.storesInDatabase() {
+		routines.Add(1)
+		go func() {
+			defer routines.Done()
+			ex.runAuditor(ctx, op)
+		}()
+	} else {
+		zap.S().Info("audit, outbox, tx event, fee and gas jobs are disabled, the sink does not write to database")
+	}
+
+	if op == BASIC_MODE && ex.storesInDatabase() {
+		routines.Add(1)
+		go func() {
+			defer routines.Done()
+			ex.runOutboxDispatcher(ctx)
+		}()
+
+		routines.Add(1)
+		go func() {
+			defer routines.Done()
+			ex.runTxEventIndexer(ctx)
+		}()
+
+		routines.Add(1)
+		go func() {
+			defer routines.Done()
+			ex.runFeeMaker(ctx)
+		}()
+
+		routines.Add(1)
+		go func() {
+			defer routines.Done()
+			ex.runGasAggregator(ctx)
+		}()
+	}
 
 	if op == BASIC_MODE {
+		routines.Add(1)
 		go func() {
+			defer routines.Done()
+
+			tick10Sec := time.NewTicker(time.Second * 10)
+			tick20Min := time.NewTicker(time.Minute * 20)
+			defer tick10Sec.Stop()
+			defer tick20Min.Stop()
+
 			for {
 				select {
 				case <-tick10Sec.C:
@@ -89,40 +191,107 @@ func (ex *Exporter) Start(op int) {
 					zap.S().Info("start sync validators keybase identities")
 					ex.saveValidatorsIdentities()
 					zap.S().Info("finish sync validators keybase identities")
-				case <-done:
+				case <-ctx.Done():
 					return
 				}
 			}
 		}()
 	}
 
-	<-done // implement gracefully shutdown when signal received
-	zap.S().Infof("shutdown signal received")
+	<-ctx.Done()
+	zap.S().Infof("shutdown signal received, waiting for running jobs")
+	routines.Wait()
+	zap.S().Infof("chain exporter stopped")
+}
+
+// storesInDatabase reports whether the sink writes to the databases of the app.
+// Jobs which read back what sync stored run only when it does.
+func (ex *Exporter) storesInDatabase() bool {
+	_, ok := ex.Sink.(*sink.Postgres)
+	return ok
+}
+
+// sleep pauses the current goroutine for d. It returns false if ctx is canceled in the meantime.
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// poll syncs every second until ctx is canceled or sync halts. When d is not 0, it returns nil after d.
+func (ex *Exporter) poll(ctx context.Context, op int, d time.Duration) error {
+	var deadline time.Time
+	if d > 0 {
+		deadline = time.Now().Add(d)
+	}
+
+	for {
+		zap.S().Info("start - sync blockchain")
+		err := ex.sync(ctx, op)
+		if errors.Is(err, errChainHalted) {
+			return err
+		}
+		if err != nil {
+			zap.S().Infof("error - sync blockchain: %s\n", err)
+		}
+		zap.S().Info("finish - sync blockchain")
+
+		if !sleep(ctx, time.Second) {
+			return ctx.Err()
+		}
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			return nil
+		}
+	}
 }
 
 // sync compares block height between the height saved in your database and
 // the latest block height on the active chain and calls process to start ingesting data.
-func (ex *Exporter) sync(op int) error {
-	// Query latest block height saved in database
-	dbHeight, err := ex.DB.GetLatestBlockHeight(ex.ChainIDMap[ex.Config.Chain.ChainID])
-	if dbHeight == -1 {
-		return fmt.Errorf("unexpected error in database: %s", err)
+func (ex *Exporter) sync(ctx context.Context, op int) error {
+	return ex.syncTo(ctx, op, 0)
+}
+
+// syncTo ingests heights up to target. The latest block height on the active chain is queried when target is 0.
+func (ex *Exporter) syncTo(ctx context.Context, op int, target int64) error {
+	// Query latest block height saved in the sink
+	dbHeight, err := ex.Sink.LatestHeight(sink.KindBasic, ex.Config.Chain.ChainID)
+	if err != nil {
+		return fmt.Errorf("unexpected error in sink: %s", err)
 	}
-	rawDBHeight, err := ex.RawDB.GetLatestBlockHeight()
-	if rawDBHeight == -1 {
-		return fmt.Errorf("unexpected error in database: %s", err)
+	rawDBHeight, err := ex.Sink.LatestHeight(sink.KindRaw, ex.Config.Chain.ChainID)
+	if err != nil {
+		return fmt.Errorf("unexpected error in sink: %s", err)
 	}
 
 	// Query latest block height on the active network
-	latestBlockHeight, err := ex.Client.RPC.GetLatestBlockHeight()
-	if latestBlockHeight == -1 {
-		return fmt.Errorf("failed to query the latest block height on the active network: %s", err)
+	latestBlockHeight := target
+	if latestBlockHeight == 0 {
+		latestBlockHeight, err = ex.Client.GetLatestBlockHeight()
+		if latestBlockHeight == -1 {
+			metrics.NodeRPCErrors.WithLabelValues("GetLatestBlockHeight").Inc()
+			return fmt.Errorf("failed to query the latest block height on the active network: %s", err)
+		}
+	}
+	metrics.ChainTipHeight.WithLabelValues(ex.Config.Chain.ChainID).Set(float64(latestBlockHeight))
+
+	if dbHeight == 0 && ex.InitialHeight != 0 {
+		dbHeight = ex.InitialHeight - 1
+		rawDBHeight = ex.InitialHeight - 1
+		zap.S().Info("initial Height set : ", ex.InitialHeight)
 	}
+	dbHeight, rawDBHeight = ex.continueLineage(dbHeight), ex.continueLineage(rawDBHeight)
+	latestBlockHeight = ex.boundLineage(latestBlockHeight)
 
-	if dbHeight == 0 && initialHeight != 0 {
-		dbHeight = initialHeight - 1
-		rawDBHeight = initialHeight - 1
-		zap.S().Info("initial Height set : ", initialHeight)
+	if op == BASIC_MODE && ex.Publisher != nil {
+		if err := ex.publishPending(ctx, dbHeight); err != nil {
+			return err
+		}
 	}
 
 	beginHeight := dbHeight
@@ -131,17 +300,44 @@ func (ex *Exporter) sync(op int) error {
 	}
 	zap.S().Infof("dbHeight %d, rawHeight %d \n", dbHeight, rawDBHeight)
 
-	for h := beginHeight + 1; h <= latestBlockHeight; h++ {
-		// block, txs, err := ex.Client.RPC.GetBlockAndTxsFromNode(custom.EncodingConfig.Marshaler, h)
-		block, txs, err := ex.Client.RPC.GetBlockAndTxsFromNode(custom.EncodingConfig.Codec, h)
-		if err != nil {
-			return fmt.Errorf("failed to get block and txs : %s", err)
+	// heights are fetched and decoded concurrently, but always committed in height order.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// block results are only stored by basic mode
+	withResults := op == BASIC_MODE && indexBlockEvents
+	fetchAt := ex.fetchBlock
+	if ex.fetch != nil {
+		fetchAt = ex.fetch
+	}
+	fetch := func(height int64) (*fetchedBlock, error) {
+		return fetchAt(ctx, height, withResults)
+	}
+
+	for fb := range fetchBlocks(ctx.Done(), beginHeight+1, latestBlockHeight, fetch) {
+		// stop between heights, so the height in progress is always committed
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if fb.err != nil {
+			return fmt.Errorf("failed to get block and txs at height %d : %s", fb.height, fb.err)
+		}
+		h, block, txs := fb.height, fb.block, fb.txs
+
+		if op == BASIC_MODE || op == RAW_MODE {
+			storedHash, ok, err := ex.verifyParent(op, block)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return ex.handleReorg(op, block, storedHash)
+			}
 		}
 
 		switch op {
 		case BASIC_MODE:
 			if h > dbHeight {
-				err = ex.process(block, txs, op)
+				err = ex.process(ctx, block, txs, fb.results, op)
 				if err != nil {
 					return err
 				}
@@ -159,33 +355,67 @@ func (ex *Exporter) sync(op int) error {
 			zap.S().Info("unknown mode = ", op)
 			os.Exit(1)
 		}
+		ex.lastBlock = blockRef{height: h, hash: block.BlockID.Hash.String()}
+		ex.beat()
 		zap.S().Infof("synced block %d/%d", h, latestBlockHeight)
 	}
 	return nil
 }
 
+// fetchBlock queries the block and decoded transactions at the height, and its block results when withResults is set.
+func (ex *Exporter) fetchBlock(ctx context.Context, height int64, withResults bool) (*fetchedBlock, error) {
+	// block, txs, err := ex.Client.RPC.GetBlockAndTxsFromNode(custom.EncodingConfig.Marshaler, height)
+	block, txs, err := ex.Client.GetBlockAndTxsFromNode(custom.EncodingConfigAt(ex.Config.Chain.ChainID, height), height)
+	if err != nil {
+		metrics.NodeRPCErrors.WithLabelValues("GetBlockAndTxsFromNode").Inc()
+		return nil, err
+	}
+	fb := &fetchedBlock{block: block, txs: txs}
+
+	if withResults {
+		fb.results, err = ex.getBlockResults(ctx, height)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return fb, nil
+}
+
 func (ex *Exporter) rawProcess(block *tmctypes.ResultBlock, txs []*sdktypes.TxResponse) (err error) {
-	rawData := new(mdschema.RawData)
+	rawData, err := ex.getRawData(block, txs)
+	if err != nil {
+		return err
+	}
+
+	err = ex.Sink.WriteRawData(rawData)
+	if err != nil {
+		return err
+	}
+	metrics.ExportedHeight.WithLabelValues(ex.Config.Chain.ChainID, metrics.ModeRaw).Set(float64(block.Block.Height))
+	return nil
+}
+
+// getRawData returns the data stored by raw mode for a block.
+func (ex *Exporter) getRawData(block *tmctypes.ResultBlock, txs []*sdktypes.TxResponse) (rawData *mdschema.RawData, err error) {
+	rawData = new(mdschema.RawData)
 
 	rawData.Block, err = ex.getRawBlock(block)
 	if err != nil {
-		return fmt.Errorf("failed to get block: %s", err)
+		return nil, fmt.Errorf("failed to get block: %s", err)
+	}
+	rawData.Transactions, err = ex.getRawTransactions(block, txs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get txs: %s", err)
 	}
-	// rawData.Transactions, err = ex.getRawTransactions(block, txs)
-	// if err != nil {
-	// 	return fmt.Errorf("failed to get txs: %s", err)
-	// }
-	return ex.RawDB.InsertExportedData(rawData)
+	return rawData, nil
 }
 
 // process ingests chain data, such as block, transaction, validator, evidence information and
-// save them in database.
-func (ex *Exporter) process(block *tmctypes.ResultBlock, txs []*sdktypes.TxResponse, op int) (err error) {
-	basic := new(mdschema.BasicData)
-
-	basic.Block, err = ex.getBlock(block)
+// save them in database. The events of results are saved with the block when results is not nil.
+func (ex *Exporter) process(ctx context.Context, block *tmctypes.ResultBlock, txs []*sdktypes.TxResponse, results *tmctypes.ResultBlockResults, op int) (err error) {
+	basic, err := ex.getBasicData(block, txs)
 	if err != nil {
-		return fmt.Errorf("failed to get block: %s", err)
+		return err
 	}
 
 	if time.Since(basic.Block.Timestamp.UTC()).Seconds() > 60 {
@@ -193,48 +423,90 @@ func (ex *Exporter) process(block *tmctypes.ResultBlock, txs []*sdktypes.TxRespo
 	} else {
 		ex.App.CatchingUp = false
 	}
+	metrics.SetCatchingUp(ex.Config.Chain.ChainID, ex.App.CatchingUp)
+
+	// notifications are saved with the block and delivered by the outbox dispatcher once the block is committed
+	entries, err := ex.getOutboxEntries(block, txs, basic)
+	if err != nil {
+		return err
+	}
+
+	e := &db.ExportedData{BasicData: basic, ChainID: block.Block.ChainID, Outbox: entries}
+	e.Events, e.Attributes = getBlockEvents(block.Block.ChainID, block.Block.Txs, results)
+
+	// the block, its outbox entries and events are written in one transaction, so the stage covers all of them
+	begin := time.Now()
+	err = ex.Sink.WriteBasicData(e)
+	if err != nil {
+		return err
+	}
+	metrics.ObserveStage(metrics.StageInsertExportedData, begin)
+	metrics.ExportedHeight.WithLabelValues(ex.Config.Chain.ChainID, metrics.ModeBasic).Set(float64(block.Block.Height))
+
+	// the height is committed, so a failed update of gas prices is only logged and retried by the next block
+	if err := ex.updateGasPrices(ctx, block.Block.Height, txs); err != nil {
+		zap.S().Errorf("failed to update gas prices: %s", err)
+	}
+
+	// the height is committed, so a failed publish is retried by publishPending on the next sync
+	if ex.Publisher != nil {
+		return ex.publishBlock(ctx, block, txs, basic)
+	}
+	return nil
+}
+
+// getBasicData decodes a block and its transactions into the data stored by basic mode.
+func (ex *Exporter) getBasicData(block *tmctypes.ResultBlock, txs []*sdktypes.TxResponse) (basic *mdschema.BasicData, err error) {
+	basic = new(mdschema.BasicData)
+
+	begin := time.Now()
+	basic.Block, err = ex.getBlock(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block: %s", err)
+	}
+	metrics.ObserveStage(metrics.StageGetBlock, begin)
 
 	basic.Evidence, err = ex.getEvidence(block)
 	if err != nil {
-		return fmt.Errorf("failed to get evidence: %s", err)
+		return nil, fmt.Errorf("failed to get evidence: %s", err)
 	}
 
 	if block.Block.LastCommit.Height != 0 {
-		prevBlock, err := ex.Client.RPC.GetBlock(block.Block.LastCommit.Height)
+		prevBlock, err := ex.Client.GetBlock(block.Block.LastCommit.Height)
 		if err != nil {
-			return fmt.Errorf("failed to query previous block: %s", err)
+			metrics.NodeRPCErrors.WithLabelValues("GetBlock").Inc()
+			return nil, fmt.Errorf("failed to query previous block: %s", err)
 		}
 
-		vals, err := ex.Client.RPC.GetValidatorsInHeight(block.Block.LastCommit.Height)
+		vals, err := ex.Client.GetValidatorsInHeight(block.Block.LastCommit.Height)
 		if err != nil {
-			return fmt.Errorf("failed to query validators: %s", err)
+			metrics.NodeRPCErrors.WithLabelValues("GetValidatorsInHeight").Inc()
+			return nil, fmt.Errorf("failed to query validators: %s", err)
 		}
 
 		basic.GenesisValidatorsSet, err = ex.getGenesisValidatorsSet(block, vals)
 		if err != nil {
-			return fmt.Errorf("failed to get genesis validator set: %s", err)
+			return nil, fmt.Errorf("failed to get genesis validator set: %s", err)
 		}
-		basic.MissBlocks, basic.AccumulatedMissBlocks, basic.MissDetailBlocks, err = ex.getValidatorsUptime(prevBlock, block, vals)
+		begin = time.Now()
+		basic.MissBlocks, basic.MissDetailBlocks, err = ex.getValidatorsUptime(prevBlock, block, vals)
 		if err != nil {
-			return fmt.Errorf("failed to get missing blocks: %s", err)
+			return nil, fmt.Errorf("failed to get missing blocks: %s", err)
 		}
+		metrics.ObserveStage(metrics.StageGetValidatorsUptime, begin)
 	}
 
 	if basic.Block.NumTxs > 0 {
-		basic.ChainInfo, err = ex.DB.GetCurrentChainInfo(ex.Config.Chain.ChainID)
-		if err != nil {
-			return fmt.Errorf("failed to get current chaininfo: %s", err)
-		}
-		basic.ChainInfo.NumberOfTxs += basic.Block.NumTxs
-
+		begin = time.Now()
 		basic.Proposals, basic.Deposits, basic.Votes, err = ex.getGovernance(&block.Block.Header.Time, txs)
 		if err != nil {
-			return fmt.Errorf("failed to get governance: %s", err)
+			return nil, fmt.Errorf("failed to get governance: %s", err)
 		}
+		metrics.ObserveStage(metrics.StageGetGovernance, begin)
 		// exportData.ValidatorsPowerEventHistory, err = ex.getPowerEventHistory(block, txs)
 		basic.ValidatorsPowerEventHistory, err = ex.getPowerEventHistoryNew(txs)
 		if err != nil {
-			return fmt.Errorf("failed to get transactions: %s", err)
+			return nil, fmt.Errorf("failed to get transactions: %s", err)
 		}
 
 		// 시작
@@ -243,17 +515,14 @@ func (ex *Exporter) process(block *tmctypes.ResultBlock, txs []*sdktypes.TxRespo
 		list[block.Block.Height] = basic.Block
 		// 종료
 
+		begin = time.Now()
 		basic.Transactions, err = ex.getTxs(block.Block.ChainID, list, txs, false)
 		if err != nil {
-			return fmt.Errorf("failed to get txs: %s", err)
+			return nil, fmt.Errorf("failed to get txs: %s", err)
 		}
+		metrics.ObserveStage(metrics.StageGetTxs, begin)
 		basic.TMAs = ex.disassembleTransaction(txs)
 	}
 
-	// TODO: is this right place to be?
-	if ex.Config.Alarm.Switch {
-		ex.handlePushNotification(block, txs)
-	}
-
-	return ex.DB.InsertExportedData(basic)
+	return basic, nil
 }
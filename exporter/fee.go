@@ -1,157 +1,241 @@
 package exporter
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmostation/cosmostation-coreum/db"
 	mdschema "github.com/cosmostation/mintscan-database/schema"
 	"go.uber.org/zap"
 )
 
+const (
+	// feeCursor is the name of the index cursor of the fee maker.
+	feeCursor = "tx_fee"
+
+	// legacyFeePointer is the name of the index pointer the fee maker kept before it was keyed by chain.
+	legacyFeePointer = "tx_fee_pointer"
+
+	// feeBatchSize is the number of transactions whose fees are extracted at a time.
+	feeBatchSize = 100
+
+	// dailyFeeRangeSize bounds the range of transaction ids rolled up at a time.
+	dailyFeeRangeSize = int64(1000)
+)
+
+// aggregateFees is the default of Exporter.AggregateFees.
+var aggregateFees = true
+
+// SetFeeOption sets whether basic mode aggregates fees of stored transactions.
+func SetFeeOption(enabled bool) {
+	aggregateFees = enabled
+	zap.S().Debugf("AggregateFees : %t\n", aggregateFees)
+}
+
 type DailyFee map[int64]sdktypes.Coins
 
-func (ex *Exporter) runFeeMaker() {
-	// database index_pointer로 부터 시작 위치를 가져옴
+// runFeeMaker extracts fees of stored transactions from the index cursor of the chain
+// and rolls them up into daily fees until ctx is canceled.
+func (ex *Exporter) runFeeMaker(ctx context.Context) {
+	if !ex.AggregateFees {
+		return
+	}
+
+	// database index_cursor로 부터 시작 위치를 가져옴
 	// error : sleep 후 다시 시도
-	// case -1 : index_pointer에 해당 마커가 없을 경우 0으로 초기화
 	// pointer로 부터 fee 추출
-	// fee, index_pointer 데이터베이스 저장 & 업데이트
-
-	indexName := "tx_fee_pointer"
-
+	// fee, index_cursor 데이터베이스 저장 & 업데이트
 	for {
-		ip, err := ex.DB.GetIndexPointer(indexName)
+		n, err := ex.makeFees()
 		if err != nil {
-			// 실패 시 재시도
-			zap.S().Error("failed to get index pointer ", err)
-			time.Sleep(time.Second * 2)
-			continue
+			zap.S().Error("failed to make fees ", err)
 		}
 
-		switch ip.Pointer {
-		case -1: // when no rows
-			ip.Pointer = 0
-			err := ex.DB.InitializeIndexPointer(ip)
-			if err != nil {
-				// 실패 시 재시도
-				zap.S().Error("failed to insert index pointer ", err)
-				time.Sleep(time.Second * 2)
-				continue
-			}
-			fallthrough
-		default:
-			newPointer, fees, dailyFee, err := ex.GetFees(ip.Pointer)
-			if err != nil {
-				zap.S().Error("failed to get fees ", err)
-				// 실패 시 재시도
-				time.Sleep(time.Second * 2)
-				continue
-			}
-			_ = dailyFee
-
-			// fee, index_pointer 저장
-			err = ex.DB.SaveFees(ip, newPointer, fees)
-			if err != nil {
-				zap.S().Error("failed to save fees  ", err)
-				time.Sleep(time.Second * 2)
-				continue
+		// fee 테이블로부터 일별 합계를 갱신
+		if err := ex.rollupDailyFees(); err != nil {
+			zap.S().Error("failed to roll up daily fees ", err)
+		}
+
+		if n == 0 || err != nil {
+			if !sleep(ctx, 2*time.Second) {
+				return
 			}
+		} else if ctx.Err() != nil {
+			return
 		}
 	}
 }
 
-func (ex *Exporter) GetFees(beginTxID int64) (int64, []mdschema.Fee, DailyFee, error) {
-	feeList := make([]mdschema.Fee, 0)
-	dailyFee := make(DailyFee)
+// makeFees saves the fees of a batch of transactions after the index cursor and moves the cursor.
+// It returns the number of transactions processed.
+func (ex *Exporter) makeFees() (int, error) {
+	chainInfoID := ex.ChainIDMap[ex.Config.Chain.ChainID]
 
-	endTxID := beginTxID
+	pointer, err := ex.feeCursor(chainInfoID)
+	if err != nil {
+		return 0, err
+	}
 
-	zap.S().Info("start aggregating fee, tx_id : ", beginTxID)
-	txs, err := ex.DB.GetTransactionsOrderBy(beginTxID, "ASC", 100)
+	newPointer, fees, payers, err := ex.GetFees(pointer)
 	if err != nil {
-		return endTxID, feeList, dailyFee, fmt.Errorf("failed to get transactions %s", err)
+		return 0, err
+	}
+	if newPointer == pointer {
+		return 0, nil
 	}
 
-	if len(txs) == 0 {
-		time.Sleep(2 * time.Second)
-		return endTxID, feeList, dailyFee, fmt.Errorf("no transactions")
+	// fee, fee payer, index_cursor 저장
+	if err := ex.DB.SaveTxFees(chainInfoID, feeCursor, newPointer, payers, fees); err != nil {
+		return 0, fmt.Errorf("failed to save fees: %s", err)
 	}
 
-	// json -> tx -> value -> fee -> amount[] -> denom, amount // sdk 0.39 below
-	// json -> tx -> auth_info -> fee -> amount[] -> denom, amount // sdk 0.40 above
-	for i := range txs {
-		raw := make(map[string]interface{})
-		err := json.Unmarshal(txs[i].Chunk, &raw)
-		if err != nil {
-			return endTxID, feeList, dailyFee, fmt.Errorf("failed to unmarshal chunk %s", err)
-		}
+	return len(payers), nil
+}
 
-		rawTx, ok := raw["tx"].(map[string]interface{})
-		if !ok {
-			return endTxID, feeList, dailyFee, fmt.Errorf("failed to assert tx")
-		}
+// feeCursor returns the pointer of the fee maker of the chain.
+// The pointer of a release which kept it in index_pointer is carried over, so that fees are not extracted twice.
+func (ex *Exporter) feeCursor(chainInfoID int) (int64, error) {
+	pointer, err := ex.DB.GetIndexCursor(chainInfoID, feeCursor)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get index cursor: %s", err)
+	}
+	if pointer > 0 {
+		return pointer, nil
+	}
 
-		aggregationDate := txs[i].Timestamp.Truncate(time.Hour * 24)
-		unixTime := aggregationDate.Unix()
+	ip, err := ex.DB.GetIndexPointer(legacyFeePointer)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get index pointer: %s", err)
+	}
+	if ip.Pointer > 0 {
+		return ip.Pointer, nil
+	}
+	return 0, nil
+}
 
-		// unix := time.Unix(unixTime, 0).UTC().Format(time.RFC3339) // daily_fee timestamp format
-		// zap.S().Info(txs[i].ID, txs[i].Timestamp, unix)
+// GetFees decodes the fees, fee payers and fee granters of the transactions after beginTxID with the codec of their height.
+// It returns the id of the last transaction read, beginTxID when there is no transaction after it.
+// A transaction whose fee can not be decoded is logged and skipped, so that it does not block the transactions after it.
+func (ex *Exporter) GetFees(beginTxID int64) (int64, []mdschema.Fee, []db.TxFeePayer, error) {
+	feeList := make([]mdschema.Fee, 0)
+	payers := make([]db.TxFeePayer, 0)
 
-		rawAuthInfo, ok := rawTx["auth_info"].(map[string]interface{}) // sdk-0.40 above
-		if !ok {
-			rawAuthInfo, ok = rawTx["value"].(map[string]interface{}) // sdk-0.33 - sdk-0.39
-			if !ok {
-				return endTxID, feeList, dailyFee, fmt.Errorf("failed to assert value and auth_info")
-			}
-		}
+	endTxID := beginTxID
 
-		fee, ok := rawAuthInfo["fee"].(map[string]interface{})
-		if !ok {
-			return endTxID, feeList, dailyFee, fmt.Errorf("failed to assert auth_info")
-		}
-		coins := make([]sdktypes.Coin, 0)
-		b, err := json.Marshal(fee["amount"])
-		if err != nil {
-			return endTxID, feeList, dailyFee, fmt.Errorf("failed to unmarshal amount to []byte %s", err)
+	zap.S().Debug("start aggregating fee, tx_id : ", beginTxID)
+	txs, err := ex.DB.GetTransactionsAfter(ex.ChainIDMap[ex.Config.Chain.ChainID], beginTxID, feeBatchSize)
+	if err != nil {
+		return endTxID, feeList, payers, fmt.Errorf("failed to get transactions %s", err)
+	}
+
+	for i := range txs {
+		if endTxID < txs[i].ID {
+			endTxID = txs[i].ID
 		}
-		err = json.Unmarshal(b, &coins)
+
+		txResp, err := ex.decodeTx(&txs[i])
 		if err != nil {
-			return endTxID, feeList, dailyFee, fmt.Errorf("failed to unmarshal to coins %s", err)
+			zap.S().Errorf("failed to get fee of tx %d, skipped : %s", txs[i].ID, err)
+			continue
 		}
 
-		df, ok := dailyFee[unixTime]
+		feeTx, ok := txResp.GetTx().(sdktypes.FeeTx)
 		if !ok {
-			df = sdktypes.NewCoins()
+			zap.S().Errorf("failed to get fee of tx %d, skipped : failed to assert fee tx %s", txs[i].ID, txs[i].Hash)
+			continue
 		}
-		dailyFee[unixTime] = df.Add(coins...)
 
-		uniqueCoins := make(map[string]sdktypes.Int)
-		for j := range coins {
-			uc, ok := uniqueCoins[coins[j].Denom]
-			if !ok {
-				uc = sdktypes.ZeroInt()
-			}
-			sum := uc.Add(coins[j].Amount)
-			uniqueCoins[coins[j].Denom] = sum
+		p := db.TxFeePayer{
+			TxID:      txs[i].ID,
+			Payer:     feeTx.FeePayer().String(),
+			Timestamp: txs[i].Timestamp,
 		}
+		if granter := feeTx.FeeGranter(); !granter.Empty() {
+			p.Granter = granter.String()
+		}
+		payers = append(payers, p)
 
-		for k, v := range uniqueCoins {
-			f := mdschema.Fee{
+		// Coins of a valid fee are sorted and have unique denoms
+		for _, c := range feeTx.GetFee() {
+			feeList = append(feeList, mdschema.Fee{
 				TxID:      txs[i].ID,
-				Denom:     k,
-				Amount:    v.String(),
+				Denom:     c.Denom,
+				Amount:    c.Amount.String(),
 				Timestamp: txs[i].Timestamp,
-			}
-			feeList = append(feeList, f)
-		}
-		if endTxID < txs[i].ID {
-			endTxID = txs[i].ID
+			})
 		}
 	}
 
-	return endTxID, feeList, dailyFee, nil
+	return endTxID, feeList, payers, nil
+}
+
+// rollupDailyFees merges the fees saved after the daily fee cursor into the daily fees of their days.
+func (ex *Exporter) rollupDailyFees() error {
+	chainInfoID := ex.ChainIDMap[ex.Config.Chain.ChainID]
+
+	pointer, err := ex.feeCursor(chainInfoID)
+	if err != nil {
+		return err
+	}
+	cursor, err := ex.DB.GetIndexCursor(chainInfoID, db.DailyFeeCursor)
+	if err != nil {
+		return fmt.Errorf("failed to get index cursor: %s", err)
+	}
+	if pointer <= cursor {
+		return nil
+	}
+
+	upTo := pointer
+	if upTo > cursor+dailyFeeRangeSize {
+		upTo = cursor + dailyFeeRangeSize
+	}
+
+	fees, err := ex.DB.GetFeesInRange(cursor, upTo)
+	if err != nil {
+		return fmt.Errorf("failed to get fees: %s", err)
+	}
+	df, err := GetDailyFee(fees)
+	if err != nil {
+		return err
+	}
+
+	days := make([]time.Time, 0, len(df))
+	for unixTime := range df {
+		days = append(days, time.Unix(unixTime, 0).UTC())
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+
+	dbdf, err := ex.DB.GetDailyFeesAt(days)
+	if err != nil {
+		return fmt.Errorf("failed to get daily fees: %s", err)
+	}
+	dfs, err := MergeDailyFee(dbdf, df)
+	if err != nil {
+		return err
+	}
+
+	if err := ex.DB.ReplaceDailyFees(chainInfoID, db.DailyFeeCursor, upTo, days, dfs); err != nil {
+		return fmt.Errorf("failed to replace daily fees: %s", err)
+	}
+	zap.S().Debugf("rolled up daily fees to tx_id %d", upTo)
+	return nil
+}
+
+// GetDailyFee sums the fees per day and denom. Days are unix times of midnight in UTC.
+func GetDailyFee(fees []mdschema.Fee) (DailyFee, error) {
+	df := make(DailyFee)
+	for i := range fees {
+		amount, ok := sdktypes.NewIntFromString(fees[i].Amount)
+		if !ok {
+			return nil, fmt.Errorf("failed to parse fee amount %s of tx_id %d", fees[i].Amount, fees[i].TxID)
+		}
+		unixTime := fees[i].Timestamp.UTC().Truncate(time.Hour * 24).Unix()
+		df[unixTime] = df[unixTime].Add(sdktypes.NewCoin(fees[i].Denom, amount))
+	}
+	return df, nil
 }
 
 func ParseDailyFee(df DailyFee) (dfs []mdschema.DailyFee) {
@@ -168,11 +252,23 @@ func ParseDailyFee(df DailyFee) (dfs []mdschema.DailyFee) {
 	return dfs
 }
 
-func MergeDailyFee(dbdf []mdschema.DailyFee, df DailyFee) (dfs []mdschema.DailyFee) {
-	// // db에서 가져와서 map 호출해서 합치고 마지막에 parse후 리턴
-	// for i := range dbdf {
-	// 	dbdf[i].Denom
-	// }
+// MergeDailyFee adds the daily fees stored in database to the daily fees of df and returns the merged daily fees.
+// Days and denoms which exist only in database are kept as they are.
+func MergeDailyFee(dbdf []mdschema.DailyFee, df DailyFee) (dfs []mdschema.DailyFee, err error) {
+	merged := make(DailyFee, len(df))
+	for unixTime, coins := range df {
+		merged[unixTime] = coins
+	}
+
+	// db에서 가져와서 map 호출해서 합치고 마지막에 parse후 리턴
+	for i := range dbdf {
+		amount, ok := sdktypes.NewIntFromString(dbdf[i].Amount)
+		if !ok {
+			return nil, fmt.Errorf("failed to parse daily fee amount %s of %s", dbdf[i].Amount, dbdf[i].Denom)
+		}
+		unixTime := dbdf[i].Timestamp.UTC().Truncate(time.Hour * 24).Unix()
+		merged[unixTime] = merged[unixTime].Add(sdktypes.NewCoin(dbdf[i].Denom, amount))
+	}
 
-	return ParseDailyFee(df)
+	return ParseDailyFee(merged), nil
 }
@@ -2,16 +2,51 @@ package exporter
 
 import (
 	"testing"
+	"time"
 
 	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	mdschema "github.com/cosmostation/mintscan-database/schema"
 	"github.com/stretchr/testify/require"
 )
 
 func TestGetFee(t *testing.T) {
+	requireFixture(t)
+
 	beginTxID := int64(2725400)
-	p, fees, dailyfees, err := ex.GetFees(beginTxID)
+	p, fees, payers, err := ex.GetFees(beginTxID)
+	require.NoError(t, err)
+	t.Log(p, fees, payers)
+}
+
+func TestMergeDailyFee(t *testing.T) {
+	day := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	next := day.Add(24 * time.Hour)
+
+	fees := []mdschema.Fee{
+		{TxID: 1, Denom: "ucore", Amount: "100", Timestamp: day.Add(time.Hour)},
+		{TxID: 2, Denom: "ucore", Amount: "50", Timestamp: day.Add(23 * time.Hour)},
+		{TxID: 3, Denom: "ucore", Amount: "7", Timestamp: next.Add(time.Minute)},
+	}
+	df, err := GetDailyFee(fees)
 	require.NoError(t, err)
-	t.Log(p, fees, dailyfees)
+	require.Equal(t, sdktypes.NewCoins(sdktypes.NewInt64Coin("ucore", 150)), df[day.Unix()])
+
+	dbdf := []mdschema.DailyFee{
+		{Denom: "ucore", Amount: "1000", Timestamp: day},
+		{Denom: "uother", Amount: "5", Timestamp: day},
+	}
+	dfs, err := MergeDailyFee(dbdf, df)
+	require.NoError(t, err)
+
+	totals := make(map[string]string)
+	for _, d := range dfs {
+		totals[d.Timestamp.Format("2006-01-02")+"/"+d.Denom] = d.Amount
+	}
+	require.Equal(t, map[string]string{
+		"2023-10-01/ucore":  "1150",
+		"2023-10-01/uother": "5",
+		"2023-10-02/ucore":  "7",
+	}, totals)
 }
 
 func TestMap(t *testing.T) {
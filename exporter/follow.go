@@ -0,0 +1,84 @@
+package exporter
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cosmostation/cosmostation-coreum/subscription"
+	"go.uber.org/zap"
+)
+
+var (
+	// wsEndpoint is the default WSEndpoint of new exporters.
+	wsEndpoint = ""
+
+	// wsIdleTimeout is how long the subscription may go without an event before it is considered dropped.
+	wsIdleTimeout = 30 * time.Second
+
+	// wsRetryInterval is how long the exporter polls after the subscription dropped before subscribing again.
+	wsRetryInterval = 30 * time.Second
+)
+
+// SetSubscriptionOption sets the websocket endpoint of new block events and how long it may be idle.
+func SetSubscriptionOption(endpoint string, idle time.Duration) {
+	wsEndpoint = endpoint
+	if idle > 0 {
+		wsIdleTimeout = idle
+	}
+	zap.S().Debugf("WSEndpoint : %s, WSIdleTimeout : %s\n", wsEndpoint, wsIdleTimeout)
+}
+
+// follow syncs each height reported by NewBlock and Tx events of the node.
+// While the subscription is not available, it falls back to polling and subscribes again after wsRetryInterval.
+func (ex *Exporter) follow(ctx context.Context, op int) error {
+	for ctx.Err() == nil {
+		sub, err := subscription.Subscribe(ctx, ex.WSEndpoint, wsIdleTimeout)
+		if err != nil {
+			zap.S().Errorf("failed to subscribe new blocks, fall back to polling: %s", err)
+		} else {
+			zap.S().Infof("subscribed new blocks on %s", ex.WSEndpoint)
+			err = ex.followSubscription(ctx, op, sub)
+			sub.Close()
+			if errors.Is(err, errChainHalted) || ctx.Err() != nil {
+				return err
+			}
+			zap.S().Errorf("subscription of new blocks dropped, fall back to polling: %s", err)
+		}
+
+		if err := ex.poll(ctx, op, wsRetryInterval); err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+// followSubscription catches up to the tip and then syncs up to every height the subscription reports.
+// It returns when the subscription ends.
+func (ex *Exporter) followSubscription(ctx context.Context, op int, sub *subscription.Subscription) error {
+	if err := ex.sync(ctx, op); err != nil {
+		if errors.Is(err, errChainHalted) {
+			return err
+		}
+		zap.S().Infof("error - sync blockchain: %s\n", err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case h, ok := <-sub.Heights():
+			if !ok {
+				return sub.Err()
+			}
+
+			// heights which failed are synced again with the next event
+			if err := ex.syncTo(ctx, op, h); err != nil {
+				if errors.Is(err, errChainHalted) {
+					return err
+				}
+				zap.S().Infof("error - sync blockchain: %s\n", err)
+			}
+		}
+	}
+}
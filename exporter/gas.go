@@ -0,0 +1,156 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cosmostation/cosmostation-coreum/db"
+	"go.uber.org/zap"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// gasStatCursor is the name of the index cursor of the gas sampling.
+	gasStatCursor = "gas_stat"
+
+	// gasStatRefreshCursor is the name of the index cursor of the gas stats refreshed from the samples.
+	gasStatRefreshCursor = "gas_stat_refresh"
+
+	// gasStatBatchSize is the number of transactions sampled at a time.
+	gasStatBatchSize = 100
+
+	// gasStatRefreshRangeSize is the range of transaction ids sampled before the stats of their buckets are refreshed
+	// while catching up, so that a bucket is not recomputed for every batch of its samples.
+	gasStatRefreshRangeSize = int64(10000)
+)
+
+// aggregateGas enables the gas aggregation in basic mode.
+var aggregateGas = true
+
+// SetGasStatOption sets whether basic mode aggregates gas used per message type.
+func SetGasStatOption(enabled bool) {
+	aggregateGas = enabled
+	zap.S().Debugf("AggregateGas : %t\n", aggregateGas)
+}
+
+// runGasAggregator samples the gas of stored transactions from the index cursor and keeps
+// the hourly and daily gas stats per message type up to date until ctx is canceled.
+func (ex *Exporter) runGasAggregator(ctx context.Context) {
+	if !aggregateGas {
+		return
+	}
+
+	for {
+		n, err := ex.aggregateGas()
+		if err != nil {
+			zap.S().Errorf("error - aggregate gas: %s", err)
+		}
+		if n < gasStatBatchSize || err != nil {
+			if !sleep(ctx, 2*time.Second) {
+				return
+			}
+		} else if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// aggregateGas samples a batch of transactions after the cursor and returns the number of transactions sampled.
+// The stats of the buckets sampled are refreshed once the range sampled since the last refresh is large enough
+// or no transaction is left to sample.
+func (ex *Exporter) aggregateGas() (int, error) {
+	chainInfoID := ex.ChainIDMap[ex.Config.Chain.ChainID]
+
+	pointer, err := ex.DB.GetIndexCursor(chainInfoID, gasStatCursor)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get index cursor: %s", err)
+	}
+
+	txs, err := ex.DB.GetTransactionsAfter(chainInfoID, pointer, gasStatBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get transactions: %s", err)
+	}
+
+	if len(txs) > 0 {
+		samples := make([]db.TxGas, 0, len(txs))
+		for i := range txs {
+			// failed transactions do not tell how much gas a message needs
+			if txs[i].Code != 0 {
+				continue
+			}
+			// a transaction which can not be decoded is skipped, so that it does not block the transactions after it
+			txResp, err := ex.decodeTx(&txs[i])
+			if err != nil {
+				zap.S().Errorf("failed to sample gas of tx %d, skipped : %s", txs[i].ID, err)
+				continue
+			}
+			samples = append(samples, ex.getTxGas(chainInfoID, txs[i].ID, txs[i].Timestamp, txResp)...)
+		}
+
+		pointer = txs[len(txs)-1].ID
+		if err := ex.DB.SaveTxGas(chainInfoID, gasStatCursor, pointer, samples); err != nil {
+			return 0, fmt.Errorf("failed to save gas samples: %s", err)
+		}
+		zap.S().Debugf("sampled gas to tx_id %d", pointer)
+	}
+
+	refreshed, err := ex.DB.GetIndexCursor(chainInfoID, gasStatRefreshCursor)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get index cursor: %s", err)
+	}
+	if pointer > refreshed && (len(txs) < gasStatBatchSize || pointer-refreshed >= gasStatRefreshRangeSize) {
+		if err := ex.DB.RefreshGasStats(chainInfoID, gasStatRefreshCursor, refreshed, pointer); err != nil {
+			return 0, fmt.Errorf("failed to refresh gas stats: %s", err)
+		}
+		zap.S().Debugf("refreshed gas stats to tx_id %d", pointer)
+	}
+
+	return len(txs), nil
+}
+
+// getTxGas samples the gas of the transaction for the message type disassembleTransaction finds in it.
+// Transactions of several message types are not sampled, since their gas can not be attributed to one type.
+// The fee is sampled only when it is paid in the bond denom.
+func (ex *Exporter) getTxGas(chainInfoID int, txID int64, ts time.Time, txResp *sdktypes.TxResponse) []db.TxGas {
+	msgs := txResp.GetTx().GetMsgs()
+	if len(msgs) == 0 {
+		return nil
+	}
+	for _, msg := range msgs[1:] {
+		if sdktypes.MsgTypeURL(msg) != sdktypes.MsgTypeURL(msgs[0]) {
+			return nil
+		}
+	}
+
+	tmas := ex.disassembleTransaction([]*sdktypes.TxResponse{txResp})
+	if len(tmas) == 0 {
+		return nil
+	}
+	msgType := tmas[0].MsgType
+	for _, tma := range tmas[1:] {
+		if tma.MsgType != msgType {
+			return nil
+		}
+	}
+
+	var fee string
+	if feeTx, ok := txResp.GetTx().(sdktypes.FeeTx); ok {
+		coins := feeTx.GetFee()
+		if amount := coins.AmountOf(ex.Network.BondDenom); len(coins) == 0 || (len(coins) == 1 && amount.IsPositive()) {
+			fee = amount.String()
+		}
+	}
+
+	return []db.TxGas{{
+		ChainInfoID: chainInfoID,
+		TxID:        txID,
+		MsgType:     msgType,
+		Height:      txResp.Height,
+		GasWanted:   txResp.GasWanted,
+		GasUsed:     txResp.GasUsed,
+		Fee:         fee,
+		Timestamp:   ts,
+	}}
+}
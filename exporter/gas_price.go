@@ -0,0 +1,171 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/cosmostation/cosmostation-coreum/db"
+	"go.uber.org/zap"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+)
+
+// percentiles of the gas prices paid in the last blocks recommended as slow, average and fast.
+const (
+	gasPriceSlow    = 25
+	gasPriceAverage = 50
+	gasPriceFast    = 75
+)
+
+// gasPriceBlocks is the number of last blocks the gas price oracle recommends from. The oracle is disabled when it is 0.
+var gasPriceBlocks = 20
+
+// SetGasPriceOption sets the number of last blocks the gas price oracle of basic mode recommends from.
+func SetGasPriceOption(blocks int) {
+	if blocks >= 0 {
+		gasPriceBlocks = blocks
+	}
+	zap.S().Debugf("GasPriceBlocks : %d\n", gasPriceBlocks)
+}
+
+// gasPriceRecommendation is the recommended gas prices of a fee denom.
+type gasPriceRecommendation struct {
+	NumTxs  int64
+	Slow    sdktypes.Dec
+	Average sdktypes.Dec
+	Fast    sdktypes.Dec
+}
+
+// gasPriceWindow keeps the gas prices paid by the transactions of the last blocks in memory,
+// so that recommendations are updated per block without reading the blocks again.
+type gasPriceWindow struct {
+	size   int
+	blocks []gasPriceBlock
+}
+
+// gasPriceBlock is the gas prices paid per fee denom by the transactions of a block.
+type gasPriceBlock struct {
+	height int64
+	prices map[string][]sdktypes.Dec
+}
+
+func newGasPriceWindow(size int) *gasPriceWindow {
+	return &gasPriceWindow{size: size}
+}
+
+// add adds the gas prices paid by the successful transactions of the block at the height. The price of a denom is
+// the fee amount of the denom per unit of gas wanted, since fees are charged for the gas limit.
+// A height which does not follow the last one starts the window again.
+func (w *gasPriceWindow) add(height int64, txs []*sdktypes.TxResponse) {
+	if n := len(w.blocks); n > 0 && w.blocks[n-1].height+1 != height {
+		w.blocks = nil
+	}
+
+	b := gasPriceBlock{height: height, prices: make(map[string][]sdktypes.Dec)}
+	for _, tx := range txs {
+		if tx.Code != 0 || tx.GasWanted <= 0 {
+			continue
+		}
+		feeTx, ok := tx.GetTx().(sdktypes.FeeTx)
+		if !ok {
+			continue
+		}
+		gas := sdktypes.NewDec(tx.GasWanted)
+		for _, c := range feeTx.GetFee() {
+			b.prices[c.Denom] = append(b.prices[c.Denom], sdktypes.NewDecFromInt(c.Amount).Quo(gas))
+		}
+	}
+
+	w.blocks = append(w.blocks, b)
+	if len(w.blocks) > w.size {
+		w.blocks = w.blocks[len(w.blocks)-w.size:]
+	}
+}
+
+// recommend returns the slow, average and fast gas prices per fee denom of the blocks in the window.
+// Prices of the denom of minGasPrice are never below it, and it is recommended as is when no transaction paid in its denom.
+func (w *gasPriceWindow) recommend(minGasPrice sdktypes.DecCoin) map[string]gasPriceRecommendation {
+	prices := make(map[string][]sdktypes.Dec)
+	for _, b := range w.blocks {
+		for denom, p := range b.prices {
+			prices[denom] = append(prices[denom], p...)
+		}
+	}
+
+	result := make(map[string]gasPriceRecommendation, len(prices)+1)
+	for denom, p := range prices {
+		sort.Slice(p, func(i, j int) bool { return p[i].LT(p[j]) })
+		result[denom] = gasPriceRecommendation{
+			NumTxs:  int64(len(p)),
+			Slow:    percentile(p, gasPriceSlow),
+			Average: percentile(p, gasPriceAverage),
+			Fast:    percentile(p, gasPriceFast),
+		}
+	}
+
+	if minGasPrice.Denom != "" {
+		r, ok := result[minGasPrice.Denom]
+		if !ok {
+			r = gasPriceRecommendation{Slow: minGasPrice.Amount, Average: minGasPrice.Amount, Fast: minGasPrice.Amount}
+		}
+		r.Slow = sdktypes.MaxDec(r.Slow, minGasPrice.Amount)
+		r.Average = sdktypes.MaxDec(r.Average, minGasPrice.Amount)
+		r.Fast = sdktypes.MaxDec(r.Fast, minGasPrice.Amount)
+		result[minGasPrice.Denom] = r
+	}
+
+	return result
+}
+
+// percentile returns the nearest-rank percentile of the sorted values.
+func percentile(sorted []sdktypes.Dec, p int) sdktypes.Dec {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// updateGasPrices adds the block to the gas price window and, once sync has caught up with the chain,
+// saves the recommendations bounded below by the current minimum gas price of the feemodel module.
+func (ex *Exporter) updateGasPrices(ctx context.Context, height int64, txs []*sdktypes.TxResponse) error {
+	if ex.gasPrices == nil {
+		return nil
+	}
+	ex.gasPrices.add(height, txs)
+
+	// the minimum gas price of the node is the current one, so past heights are not recommended from
+	if ex.App.CatchingUp {
+		return nil
+	}
+
+	minGasPrice, err := ex.Client.GetMinGasPrice(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to query min gas price: %s", err)
+	}
+
+	recommendations := ex.gasPrices.recommend(minGasPrice)
+	prices := make([]db.GasPrice, 0, len(recommendations))
+	for denom, r := range recommendations {
+		floor := sdktypes.ZeroDec()
+		if denom == minGasPrice.Denom {
+			floor = minGasPrice.Amount
+		}
+		prices = append(prices, db.GasPrice{
+			ChainInfoID: ex.ChainIDMap[ex.Config.Chain.ChainID],
+			Denom:       denom,
+			Height:      height,
+			NumTxs:      r.NumTxs,
+			Slow:        r.Slow.String(),
+			Average:     r.Average.String(),
+			Fast:        r.Fast.String(),
+			MinGasPrice: floor.String(),
+		})
+	}
+
+	if err := ex.Sink.WriteGasPrices(ex.ChainIDMap[ex.Config.Chain.ChainID], prices); err != nil {
+		return fmt.Errorf("failed to replace gas prices: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package exporter
+
+import (
+	"testing"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/stretchr/testify/require"
+)
+
+func newFeeTxResponse(t *testing.T, height int64, code uint32, gasWanted int64, fee sdktypes.Coins) *sdktypes.TxResponse {
+	txAny, err := codectypes.NewAnyWithValue(&txtypes.Tx{
+		Body:     &txtypes.TxBody{},
+		AuthInfo: &txtypes.AuthInfo{Fee: &txtypes.Fee{Amount: fee, GasLimit: uint64(gasWanted)}},
+	})
+	require.NoError(t, err)
+	return &sdktypes.TxResponse{Height: height, Code: code, GasWanted: gasWanted, Tx: txAny}
+}
+
+func TestGasPriceWindow(t *testing.T) {
+	w := newGasPriceWindow(2)
+	ucore := func(amount int64) sdktypes.Coins { return sdktypes.NewCoins(sdktypes.NewInt64Coin("ucore", amount)) }
+
+	w.add(1, []*sdktypes.TxResponse{newFeeTxResponse(t, 1, 0, 1000, ucore(100000))}) // 100, dropped by the window size
+	w.add(2, []*sdktypes.TxResponse{
+		newFeeTxResponse(t, 2, 0, 1000, ucore(1000)),  // 1
+		newFeeTxResponse(t, 2, 0, 1000, ucore(2000)),  // 2
+		newFeeTxResponse(t, 2, 5, 1000, ucore(90000)), // failed
+	})
+	w.add(3, []*sdktypes.TxResponse{
+		newFeeTxResponse(t, 3, 0, 1000, ucore(3000)), // 3
+		newFeeTxResponse(t, 3, 0, 1000, ucore(4000)), // 4
+		newFeeTxResponse(t, 3, 0, 100, sdktypes.NewCoins(sdktypes.NewInt64Coin("uother", 50))),
+	})
+
+	r := w.recommend(sdktypes.NewDecCoinFromDec("ucore", sdktypes.NewDecWithPrec(15, 1)))
+	require.Len(t, r, 2)
+	require.Equal(t, int64(4), r["ucore"].NumTxs)
+	require.Equal(t, "1.500000000000000000", r["ucore"].Slow.String()) // bounded by the min gas price
+	require.Equal(t, "2.000000000000000000", r["ucore"].Average.String())
+	require.Equal(t, "3.000000000000000000", r["ucore"].Fast.String())
+	require.Equal(t, "0.500000000000000000", r["uother"].Average.String())
+
+	// a height which does not follow the window starts it again
+	w.add(10, nil)
+	r = w.recommend(sdktypes.NewDecCoinFromDec("ucore", sdktypes.NewDecWithPrec(15, 1)))
+	require.Len(t, r, 1)
+	require.Equal(t, int64(0), r["ucore"].NumTxs)
+	require.Equal(t, "1.500000000000000000", r["ucore"].Fast.String())
+}
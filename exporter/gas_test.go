@@ -0,0 +1,69 @@
+package exporter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cosmostation/cosmostation-coreum/custom"
+	"github.com/stretchr/testify/require"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+)
+
+func newMsgsTxResponse(t *testing.T, fee sdktypes.Coins, msgs ...sdktypes.Msg) *sdktypes.TxResponse {
+	anys := make([]*codectypes.Any, len(msgs))
+	for i := range msgs {
+		msgAny, err := codectypes.NewAnyWithValue(msgs[i])
+		require.NoError(t, err)
+		anys[i] = msgAny
+	}
+	txAny, err := codectypes.NewAnyWithValue(&txtypes.Tx{
+		Body:     &txtypes.TxBody{Messages: anys},
+		AuthInfo: &txtypes.AuthInfo{Fee: &txtypes.Fee{Amount: fee, GasLimit: 200000}},
+	})
+	require.NoError(t, err)
+	return &sdktypes.TxResponse{Height: 10, GasWanted: 200000, GasUsed: 150000, Tx: txAny}
+}
+
+func TestGetTxGas(t *testing.T) {
+	requireFixture(t)
+
+	from := sdktypes.AccAddress(make([]byte, 20)).String()
+	to := sdktypes.AccAddress(append(make([]byte, 19), 1)).String()
+	amount := sdktypes.NewCoins(sdktypes.NewInt64Coin(custom.CurrentNetwork.BondDenom, 1))
+	send := &banktypes.MsgSend{FromAddress: from, ToAddress: to, Amount: amount}
+	multiSend := &banktypes.MsgMultiSend{
+		Inputs:  []banktypes.Input{{Address: from, Coins: amount}},
+		Outputs: []banktypes.Output{{Address: to, Coins: amount}},
+	}
+	bondFee := sdktypes.NewCoins(sdktypes.NewInt64Coin(custom.CurrentNetwork.BondDenom, 5000))
+	ts := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	// a transaction of a message type is sampled with its whole gas
+	samples := ex.getTxGas(1, 100, ts, newMsgsTxResponse(t, bondFee, send, send))
+	require.Len(t, samples, 1)
+	require.Equal(t, int64(150000), samples[0].GasUsed)
+	require.Equal(t, int64(200000), samples[0].GasWanted)
+	require.Equal(t, "5000", samples[0].Fee)
+	require.NotEmpty(t, samples[0].MsgType)
+
+	// gas of several message types can not be attributed to one of them
+	require.Empty(t, ex.getTxGas(1, 101, ts, newMsgsTxResponse(t, bondFee, send, multiSend)))
+
+	// fees paid in other denoms are not sampled
+	otherFee := sdktypes.NewCoins(sdktypes.NewInt64Coin("uother", 5000))
+	samples = ex.getTxGas(1, 102, ts, newMsgsTxResponse(t, otherFee, send))
+	require.Len(t, samples, 1)
+	require.Empty(t, samples[0].Fee)
+
+	samples = ex.getTxGas(1, 103, ts, newMsgsTxResponse(t, bondFee.Add(otherFee...), send))
+	require.Len(t, samples, 1)
+	require.Empty(t, samples[0].Fee)
+
+	samples = ex.getTxGas(1, 104, ts, newMsgsTxResponse(t, nil, send))
+	require.Len(t, samples, 1)
+	require.Equal(t, "0", samples[0].Fee)
+}
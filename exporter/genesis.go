@@ -129,12 +129,12 @@ func (ex *Exporter) getGenesisAccounts(genesisAccts authtypes.GenesisAccounts) (
 		return []mdschema.AccountCoin{}, err
 	}
 
-	block, err := ex.Client.RPC.GetBlock(startingHeight)
+	block, err := ex.Client.GetBlock(startingHeight)
 	if err != nil {
 		return []mdschema.AccountCoin{}, err
 	}
 
-	denom, err := ex.Client.GRPC.GetBondDenom(context.Background())
+	denom, err := ex.Client.GetBondDenom(context.Background())
 	if err != nil {
 		return []mdschema.AccountCoin{}, err
 	}
@@ -288,7 +288,7 @@ func (ex *Exporter) getGenesisValidatorsSet(block *tmctypes.ResultBlock, vals *t
 		return []mdschema.PowerEventHistory{}, nil
 	}
 
-	denom, err := ex.Client.GRPC.GetBondDenom(context.Background())
+	denom, err := ex.Client.GetBondDenom(context.Background())
 	if err != nil {
 		return []mdschema.PowerEventHistory{}, err
 	}
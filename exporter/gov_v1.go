@@ -18,7 +18,7 @@ func (ex *Exporter) GetAllProposals_v1() (result []mdschema.Proposal, err error)
 	keyExists := true
 	var nextKey []byte
 	for keyExists {
-		res, err := ex.Client.GRPC.GetProposals_v1(context.Background(), nextKey)
+		res, err := ex.Client.GetProposals_v1(context.Background(), nextKey)
 		if err != nil {
 			return []mdschema.Proposal{}, fmt.Errorf("failed to request gov proposals: %s", err)
 		}
@@ -46,7 +46,7 @@ func (ex *Exporter) GetAllProposals_v1() (result []mdschema.Proposal, err error)
 
 // GetProposal_v1은 특정 프로포절 정보를 GRPC 얻어온다.
 func (ex *Exporter) GetProposal_v1(id uint64) (result *mdschema.Proposal, err error) {
-	p, err := ex.Client.GRPC.GetProposal_v1(context.Background(), id)
+	p, err := ex.Client.GetProposal_v1(context.Background(), id)
 	if err != nil {
 		return result, fmt.Errorf("failed to request gov proposals: %s", err)
 	}
@@ -70,7 +70,7 @@ func (ex *Exporter) transformProposal_v1(cdc codec.Codec, p *v1.Proposal) (resul
 	// total depoist : amount / denom
 	tda, tdd := govutil.CoinsToString_v1(p.TotalDeposit)
 
-	tally, err := ex.Client.GRPC.GetProposalTallyResult_v1(context.Background(), p.Id)
+	tally, err := ex.Client.GetProposalTallyResult_v1(context.Background(), p.Id)
 	if err != nil {
 		return result, fmt.Errorf("failed to request gov proposals: %s", err)
 	}
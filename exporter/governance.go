@@ -1,8 +1,8 @@
 package exporter
 
 import (
+	"context"
 	"strconv"
-	"sync"
 	"time"
 
 	"github.com/cosmostation/cosmostation-coreum/custom"
@@ -18,8 +18,6 @@ import (
 	"go.uber.org/zap"
 )
 
-var muProp sync.RWMutex
-
 const (
 	SUBMIT_PROPOSAL = iota
 	DEPOSIT         // update total deposit & proposal status
@@ -31,47 +29,51 @@ type propFlag struct {
 	Flag   int // deposit
 }
 
-var propList = make(map[uint64]struct{})
-
-func (ex *Exporter) watchLiveProposals() {
+func (ex *Exporter) watchLiveProposals(ctx context.Context) {
 	for {
 		p, err := ex.DB.GetLiveProposalIDs()
 		if err != nil {
 			zap.S().Info("failed to get live proposals")
-			time.Sleep(2 * time.Second)
+			if !sleep(ctx, 2*time.Second) {
+				return
+			}
 			continue
 		}
-		muProp.Lock()
+		ex.muProp.Lock()
 		for i := range p {
 			// _ := govtypesv1.ProposalStatus_value[p[i].ProposalStatus]
-			_, ok := propList[p[i].ID]
+			_, ok := ex.propList[p[i].ID]
 			if !ok {
-				propList[p[i].ID] = struct{}{}
+				ex.propList[p[i].ID] = struct{}{}
 			}
 		}
-		muProp.Unlock()
+		ex.muProp.Unlock()
 		zap.S().Info("proposal list updated")
-		time.Sleep(6 * time.Second)
+		if !sleep(ctx, 6*time.Second) {
+			return
+		}
 	}
 }
 
-func (ex *Exporter) updateProposals() {
+func (ex *Exporter) updateProposals(ctx context.Context) {
 	for {
 		if !ex.App.CatchingUp {
-			zap.S().Info("start updating proposals : ", propList)
-			muProp.RLock()
-			for id := range propList {
+			ex.muProp.Lock()
+			zap.S().Info("start updating proposals : ", ex.propList)
+			for id := range ex.propList {
 				if err := ex.updateProposal(id); err != nil {
 					continue
 				}
-				delete(propList, id)
+				delete(ex.propList, id)
 			}
-			muProp.RUnlock()
-			zap.S().Info("finish update proposals : ", propList)
+			zap.S().Info("finish update proposals : ", ex.propList)
+			ex.muProp.Unlock()
 		} else {
 			zap.S().Info("pending update proposals, app is catching up")
 		}
-		time.Sleep(10 * time.Second)
+		if !sleep(ctx, 10*time.Second) {
+			return
+		}
 	}
 }
 
@@ -193,8 +195,6 @@ func (ex *Exporter) govRoute(ps *[]mdschema.Proposal, ds *[]mdschema.Deposit, vs
 		}
 		*ds = append(*ds, d)
 
-		go ex.ProposalNotificationToSlack(p.ID)
-
 	case *govtypesv1beta1.MsgDeposit:
 		zap.S().Infof("MsgType: %s | Hash: %s", m.Type(), tx.TxHash)
 
@@ -219,7 +219,6 @@ func (ex *Exporter) govRoute(ps *[]mdschema.Proposal, ds *[]mdschema.Deposit, vs
 		}
 
 		*ds = append(*ds, d)
-		go ex.ProposalNotificationToSlack(d.ProposalID)
 
 	case *govtypesv1beta1.MsgVote:
 		zap.S().Infof("MsgType: %s | Hash: %s", m.Type(), tx.TxHash)
@@ -339,8 +338,6 @@ func (ex *Exporter) govRoute(ps *[]mdschema.Proposal, ds *[]mdschema.Deposit, vs
 		}
 		*ds = append(*ds, d)
 
-		go ex.ProposalNotificationToSlack(p.ID)
-
 	case *govtypesv1.MsgDeposit:
 		zap.S().Infof("MsgType: %s | Hash: %s", m.Type(), tx.TxHash)
 
@@ -365,7 +362,6 @@ func (ex *Exporter) govRoute(ps *[]mdschema.Proposal, ds *[]mdschema.Deposit, vs
 		}
 
 		*ds = append(*ds, d)
-		go ex.ProposalNotificationToSlack(d.ProposalID)
 
 	case *govtypesv1.MsgVote:
 		zap.S().Infof("MsgType: %s | Hash: %s", m.Type(), tx.TxHash)
@@ -408,7 +404,7 @@ func (ex *Exporter) govRoute(ps *[]mdschema.Proposal, ds *[]mdschema.Deposit, vs
 
 // saveProposals saves all governance proposals
 func (ex *Exporter) saveAllProposals() {
-	NodePropCount, err := ex.Client.GRPC.GetNumberofProposals_v1()
+	NodePropCount, err := ex.Client.GetNumberofProposals_v1()
 	if err != nil {
 		zap.S().Errorf("failed to get number of proposal from DB: %s", err)
 		return
@@ -10,6 +10,7 @@ import (
 	sdktypes "github.com/cosmos/cosmos-sdk/types"
 	v1 "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
 	"github.com/cosmostation/cosmostation-coreum/custom"
+	"github.com/cosmostation/cosmostation-coreum/db"
 	govutil "github.com/cosmostation/mintscan-backend-library/types"
 
 	mdschema "github.com/cosmostation/mintscan-database/schema"
@@ -18,6 +19,8 @@ import (
 )
 
 func TestGetProposals(t *testing.T) {
+	requireFixture(t)
+
 	resp, err := ex.GetAllProposals_v1()
 	require.NoError(t, err)
 
@@ -29,10 +32,14 @@ func TestGetProposals(t *testing.T) {
 	}
 }
 func TestSaveAllProposals(t *testing.T) {
+	requireFixture(t)
+
 	ex.saveAllProposals()
 }
 
 func TestGetRecoveryVote(t *testing.T) {
+	requireFixture(t)
+
 	// beginTxID := int64(35778600)
 	beginTxID := int64(62636577)
 	msgType := "gov/vote"
@@ -65,12 +72,14 @@ func TestGetRecoveryVote(t *testing.T) {
 		basic := new(mdschema.BasicData)
 		basic.Proposals, basic.Deposits, basic.Votes, err = ex.getGovernance(nil, txResps)
 		require.NoError(t, err)
-		ex.DB.InsertExportedData(basic)
+		ex.DB.InsertExportedData(&db.ExportedData{BasicData: basic})
 
 	}
 }
 
 func TestUpdateProposal(t *testing.T) {
+	requireFixture(t)
+
 	// 기존 프로포절에 IPFS를 강제로 삽입하여, IPFS 로직이 정상동작하는지 테스트
 	p, err := ex.GetProposal_v1(15)
 	require.NoError(t, err)
@@ -107,6 +116,8 @@ func TestUpdateProposal(t *testing.T) {
 }
 
 func TestGetProposalUpdateNeeded(t *testing.T) {
+	requireFixture(t)
+
 	ps, err := ex.DB.GetProposalUpdateNeeded()
 	require.NoError(t, err)
 
@@ -115,6 +126,8 @@ func TestGetProposalUpdateNeeded(t *testing.T) {
 }
 
 func TestGetProposals_v1(t *testing.T) {
+	requireFixture(t)
+
 	keyExists := true
 	nextKey := make([]byte, 8)
 	binary.BigEndian.PutUint64(nextKey, 0)
@@ -183,6 +196,8 @@ func TestGetProposals_v1(t *testing.T) {
 }
 
 func TestSaveAllProposalsWithoutCondition(t *testing.T) {
+	requireFixture(t)
+
 	proposals, err := ex.GetAllProposals_v1()
 	require.NoError(t, err)
 
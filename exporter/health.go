@@ -0,0 +1,107 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cosmostation/cosmostation-coreum/health"
+	"github.com/cosmostation/cosmostation-coreum/sink"
+	"go.uber.org/zap"
+)
+
+var (
+	// maxHeightLag is the number of heights the stored height may fall behind the node before the exporter is not ready.
+	maxHeightLag = int64(100)
+
+	// stallTimeout is how long sync may go without progress before the exporter is not live.
+	stallTimeout = 5 * time.Minute
+)
+
+// SetHealthOption sets the thresholds of readiness and liveness probes.
+func SetHealthOption(lag int64, stall time.Duration) {
+	if lag > 0 {
+		maxHeightLag = lag
+	}
+	if stall > 0 {
+		stallTimeout = stall
+	}
+	zap.S().Debugf("MaxHeightLag : %d, StallTimeout : %s\n", maxHeightLag, stallTimeout)
+}
+
+// beat records that sync made progress. It is called only when a height is exported,
+// so that sync failing on every cycle fails the liveness check.
+func (ex *Exporter) beat() {
+	ex.heartbeat.Store(time.Now().UnixNano())
+}
+
+// LivenessChecks returns checks which fail when sync of the mode stopped making progress,
+// so that the exporter is restarted.
+func (ex *Exporter) LivenessChecks(op int) health.Checks {
+	ex.beat()
+
+	switch op {
+	case BASIC_MODE, RAW_MODE, REFINE_MODE, BACKFILL_MODE, ARCHIVE_MODE:
+		return health.Checks{"sync": ex.checkHeartbeat}
+	}
+	return health.Checks{}
+}
+
+// ReadinessChecks returns checks of the databases, the node and the height lag of the mode.
+func (ex *Exporter) ReadinessChecks(op int) health.Checks {
+	checks := health.Checks{
+		"db": health.Bound(func() error {
+			return ex.DB.Ping()
+		}),
+		"rawdb": health.Bound(func() error {
+			return ex.RawDB.Ping()
+		}),
+		"node": health.Bound(func() error {
+			_, err := ex.Client.GetStatus()
+			return err
+		}),
+	}
+
+	switch op {
+	case BASIC_MODE, RAW_MODE, REFINE_MODE:
+		checks["lag"] = health.Bound(func() error {
+			return ex.checkLag(op)
+		})
+	}
+
+	return checks
+}
+
+// checkHeartbeat fails when sync has not made progress for stallTimeout.
+func (ex *Exporter) checkHeartbeat(ctx context.Context) error {
+	last := time.Unix(0, ex.heartbeat.Load())
+	if since := time.Since(last); since > stallTimeout {
+		return fmt.Errorf("no sync progress for %s", since.Truncate(time.Second))
+	}
+	return nil
+}
+
+// checkLag fails when the height stored by the mode is more than maxHeightLag behind the node.
+func (ex *Exporter) checkLag(op int) error {
+	status, err := ex.Client.GetStatus()
+	if err != nil {
+		return fmt.Errorf("failed to get status: %s", err)
+	}
+	if status.SyncInfo.CatchingUp {
+		return fmt.Errorf("node is catching up")
+	}
+
+	kind := sink.KindBasic
+	if op == RAW_MODE {
+		kind = sink.KindRaw
+	}
+	height, err := ex.Sink.LatestHeight(kind, ex.Config.Chain.ChainID)
+	if err != nil {
+		return fmt.Errorf("failed to get latest block height in sink: %s", err)
+	}
+
+	if lag := status.SyncInfo.LatestBlockHeight - height; lag > maxHeightLag {
+		return fmt.Errorf("stored height %d is %d behind the node", height, lag)
+	}
+	return nil
+}
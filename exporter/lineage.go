@@ -0,0 +1,28 @@
+package exporter
+
+// lineageBounds returns the first and the last height produced under the chain-id of the exporter
+// according to the chain lineage. Both are 0 when the chain-id is not in the lineage, and last is 0 while the chain is live.
+func (ex *Exporter) lineageBounds() (first, last int64) {
+	seg, ok := ex.ChainSegment(ex.Config.Chain.ChainID)
+	if !ok {
+		return 0, 0
+	}
+	return seg.StartHeight, seg.EndHeight
+}
+
+// continueLineage returns the stored height of a relaunched chain-id as at least the height before its first height,
+// so that sync continues right after the last height of the previous chain-id.
+func (ex *Exporter) continueLineage(stored int64) int64 {
+	if first, _ := ex.lineageBounds(); first > 1 && stored < first-1 {
+		return first - 1
+	}
+	return stored
+}
+
+// boundLineage returns the target height bounded by the last height of a chain-id which has been succeeded.
+func (ex *Exporter) boundLineage(target int64) int64 {
+	if _, last := ex.lineageBounds(); last > 0 && target > last {
+		return last
+	}
+	return target
+}
@@ -1,8 +1,10 @@
 package exporter
 
 const (
-	BASIC_MODE   = 1
-	RAW_MODE     = 2
-	REFINE_MODE  = 3
-	GENESIS_MODE = 4
+	BASIC_MODE    = 1
+	RAW_MODE      = 2
+	REFINE_MODE   = 3
+	GENESIS_MODE  = 4
+	BACKFILL_MODE = 5
+	ARCHIVE_MODE  = 6
 )
@@ -22,11 +22,17 @@ import (
 	tmctypes "github.com/cometbft/cometbft/rpc/core/types"
 )
 
-// handlePushNotification handles our mobile wallet applications' push notification.
-func (ex *Exporter) handlePushNotification(block *tmctypes.ResultBlock, txResp []*sdktypes.TxResponse) error {
-	if len(txResp) <= 0 {
-		return nil
-	}
+// pushNotification is a push notification to an account which sent or received tokens.
+type pushNotification struct {
+	Address string                    `json:"address"`
+	Target  string                    `json:"target"`
+	Payload types.NotificationPayload `json:"payload"`
+}
+
+// getPushNotifications returns push notifications for our mobile wallet applications
+// of the tokens sent by successful transactions.
+func getPushNotifications(txResp []*sdktypes.TxResponse) []pushNotification {
+	pushes := make([]pushNotification, 0)
 
 	for _, tx := range txResp {
 		// Other than code equals to 0, it is failed transaction.
@@ -37,19 +43,10 @@ func (ex *Exporter) handlePushNotification(block *tmctypes.ResultBlock, txResp [
 		msgs := tx.GetTx().GetMsgs()
 
 		for _, msg := range msgs {
-
-			// stdTx, ok := tx.Tx.(auth.StdTx)
-			// if !ok {
-			// 	return fmt.Errorf("unsupported tx type: %s", tx.Tx)
-			// }
-
 			switch m := msg.(type) {
-			// case bank.MsgSend:
 			case *banktypes.MsgSend:
 				zap.S().Infof("MsgType: %s | Hash: %s", m.Type(), tx.TxHash)
 
-				// msgSend := m.(banktypestypes.MsgSend)
-
 				var amount string
 				var denom string
 
@@ -68,31 +65,13 @@ func (ex *Exporter) handlePushNotification(block *tmctypes.ResultBlock, txResp [
 				})
 
 				// Push notification to both sender and recipient.
-				notification := notification.NewNotification()
-
-				fromAccountStatus := notification.VerifyAccountStatus(m.FromAddress)
-				if fromAccountStatus {
-					tokens, _ := ex.DB.QueryAlarmTokens(m.FromAddress)
-					if len(tokens) > 0 {
-						notification.Push(*payload, tokens, types.From)
-					}
-				}
-
-				toAccountStatus := notification.VerifyAccountStatus(m.ToAddress)
-				if toAccountStatus {
-					tokens, _ := ex.DB.QueryAlarmTokens(m.ToAddress)
-					if len(tokens) > 0 {
-						notification.Push(*payload, tokens, types.To)
-					}
-				}
+				pushes = append(pushes,
+					pushNotification{Address: m.FromAddress, Target: types.From, Payload: *payload},
+					pushNotification{Address: m.ToAddress, Target: types.To, Payload: *payload})
 
 			case *banktypes.MsgMultiSend:
 				zap.S().Infof("MsgType: %s | Hash: %s", m.Type(), tx.TxHash)
 
-				// msgMultiSend := m.(banktypes.MsgMultiSend)
-
-				notification := notification.NewNotification()
-
 				// Push notifications to all accounts in inputs
 				for _, input := range m.Inputs {
 					var amount string
@@ -103,20 +82,13 @@ func (ex *Exporter) handlePushNotification(block *tmctypes.ResultBlock, txResp [
 						denom = input.Coins[0].Denom
 					}
 
-					payload := &types.NotificationPayload{
+					payload := types.NotificationPayload{
 						From:   input.Address,
 						Txid:   tx.TxHash,
 						Amount: amount,
 						Denom:  denom,
 					}
-
-					fromAccountStatus := notification.VerifyAccountStatus(input.Address)
-					if fromAccountStatus {
-						tokens, _ := ex.DB.QueryAlarmTokens(input.Address)
-						if len(tokens) > 0 {
-							notification.Push(*payload, tokens, types.From)
-						}
-					}
+					pushes = append(pushes, pushNotification{Address: input.Address, Target: types.From, Payload: payload})
 				}
 
 				// Push notifications to all accounts in outputs
@@ -129,20 +101,13 @@ func (ex *Exporter) handlePushNotification(block *tmctypes.ResultBlock, txResp [
 						denom = output.Coins[0].Denom
 					}
 
-					payload := &types.NotificationPayload{
+					payload := types.NotificationPayload{
 						To:     output.Address,
 						Txid:   tx.TxHash,
 						Amount: amount,
 						Denom:  denom,
 					}
-
-					toAcctStatus := notification.VerifyAccountStatus(output.Address)
-					if toAcctStatus {
-						tokens, _ := ex.DB.QueryAlarmTokens(output.Address)
-						if len(tokens) > 0 {
-							notification.Push(*payload, tokens, types.To)
-						}
-					}
+					pushes = append(pushes, pushNotification{Address: output.Address, Target: types.To, Payload: payload})
 				}
 
 			default:
@@ -151,7 +116,24 @@ func (ex *Exporter) handlePushNotification(block *tmctypes.ResultBlock, txResp [
 		}
 	}
 
-	return nil
+	return pushes
+}
+
+// deliverPushNotification pushes the notification to the devices of the account if the account enabled the alarm.
+func (ex *Exporter) deliverPushNotification(nof *notification.Notification, p pushNotification) error {
+	if !nof.VerifyAccountStatus(p.Address) {
+		return nil
+	}
+
+	tokens, err := ex.DB.QueryAlarmTokens(p.Address)
+	if err != nil {
+		return fmt.Errorf("failed to query alarm tokens: %s", err)
+	}
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	return nof.Push(p.Payload, tokens, p.Target)
 }
 
 // SlackRequestBody is a type for sending a message to Slack.
@@ -193,7 +175,7 @@ func (ex *Exporter) NotificationToSlack(msg, url string) error {
 func (ex *Exporter) SetMessageForProposalOccur(proposal *mdschema.Proposal) string {
 	uri := ex.Config.Web.URI
 
-	chainID, err := ex.App.Client.RPC.GetNetworkChainID()
+	chainID, err := ex.App.Client.GetNetworkChainID()
 	if err != nil {
 		url, err := url.Parse(uri)
 		if err != nil {
@@ -219,7 +201,7 @@ func (ex *Exporter) SetMessageForProposalOccur(proposal *mdschema.Proposal) stri
 func (ex *Exporter) SetMessageForVoting(proposal *mdschema.Proposal) string {
 	uri := ex.Config.Web.URI
 
-	chainID, err := ex.App.Client.RPC.GetNetworkChainID()
+	chainID, err := ex.App.Client.GetNetworkChainID()
 	if err != nil {
 		url, err := url.Parse(uri)
 		if err != nil {
@@ -237,20 +219,20 @@ func (ex *Exporter) SetMessageForVoting(proposal *mdschema.Proposal) string {
 		proposal.VotingEndTime, uri, proposal.ID)
 }
 
-// ProposalNotificationToSlack 함수는 슬랙에 메시지를 보내기 위한 함수로 고루틴을 사용해서 실행해야함
-func (ex *Exporter) ProposalNotificationToSlack(id uint64) {
+// ProposalNotificationToSlack 함수는 프로포절 상태에 따라 슬랙에 메시지를 보내고 알림 상태를 갱신한다.
+// 에러를 리턴하면 outbox dispatcher가 재시도한다.
+func (ex *Exporter) ProposalNotificationToSlack(id uint64) error {
 
 	// LCD를 통해 프로포절 정보를 받아오는 것은 DB로만 정보를 받아올 경우 투표기간 돌입 상태를 확인 못할수도 있기 때문
 	proposalByLCD, err := ex.GetProposal_v1(id)
 	if err != nil {
-		zap.L().Error("failed get proposal info from lcd", zap.Error(err))
+		return fmt.Errorf("failed get proposal info from lcd: %s", err)
 	}
 
 	//TODO : 추후 버전을 위해 noti 상태 + 프로포절 상태 받아오는 함수 만들기
 	proposalByDB, err := ex.DB.GetProposal(id)
-
 	if err != nil {
-		zap.L().Error("failed query proposal noti status", zap.Error(err))
+		return fmt.Errorf("failed query proposal noti status: %s", err)
 	}
 
 	//테스트용
@@ -261,26 +243,27 @@ func (ex *Exporter) ProposalNotificationToSlack(id uint64) {
 	if (propStatus == mbltypes.StatusRejected || propStatus == mbltypes.StatusPassed) && (notificationStatus != mbltypes.VOTINGNOTIFIED) {
 		err := ex.DB.UpdateProposalNotiStatus(id, mbltypes.VOTINGNOTIFIED)
 		if err != nil {
-			zap.L().Error("failed insert or update proposal noti status", zap.Error(err))
+			return fmt.Errorf("failed insert or update proposal noti status: %s", err)
 		}
 	} else if propStatus == mbltypes.StatusVotingPeriod && notificationStatus != mbltypes.VOTINGNOTIFIED {
 		err := ex.NotificationToSlack(ex.SetMessageForVoting(proposalByLCD), ex.Config.Slack.WebHook)
 		if err != nil {
-			zap.L().Error("failed proposal voting notification to slack ", zap.Error(err))
+			return fmt.Errorf("failed proposal voting notification to slack: %s", err)
 		}
 		err = ex.DB.UpdateProposalNotiStatus(id, mbltypes.VOTINGNOTIFIED)
 		if err != nil {
-			zap.L().Error("failed insert or update proposal noti status", zap.Error(err))
+			return fmt.Errorf("failed insert or update proposal noti status: %s", err)
 		}
 	} else if propStatus == mbltypes.StatusDepositPeriod && notificationStatus != mbltypes.SUBMITNOTIFIED {
 		err := ex.NotificationToSlack(ex.SetMessageForProposalOccur(proposalByLCD), ex.Config.Slack.WebHook)
 		if err != nil {
-			zap.L().Error("failed proposal occur notification to slack ", zap.Error(err))
+			return fmt.Errorf("failed proposal occur notification to slack: %s", err)
 		}
 		err = ex.DB.UpdateProposalNotiStatus(id, mbltypes.SUBMITNOTIFIED)
 		if err != nil {
-			zap.L().Error("failed insert or update proposal noti status", zap.Error(err))
+			return fmt.Errorf("failed insert or update proposal noti status: %s", err)
 		}
 	}
 
+	return nil
 }
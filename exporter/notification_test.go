@@ -16,6 +16,8 @@ var (
 // TODO: no available tx hash in mainnet
 
 func TestProposalAlarm(t *testing.T) {
+	requireFixture(t)
+
 	chainEx := app.NewApp("chain-exporter")
 	ex = NewExporter(chainEx)
 	go ex.ProposalNotificationToSlack(6)
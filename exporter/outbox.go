@@ -0,0 +1,188 @@
+package exporter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/cosmostation/cosmostation-coreum/db"
+	"github.com/cosmostation/cosmostation-coreum/notification"
+	"go.uber.org/zap"
+
+	mdschema "github.com/cosmostation/mintscan-database/schema"
+
+	tmctypes "github.com/cometbft/cometbft/rpc/core/types"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Kinds of outbox entries.
+const (
+	OUTBOX_PUSH           = "push"
+	OUTBOX_SLACK_PROPOSAL = "slack_proposal"
+)
+
+var (
+	// outboxInterval is the interval between rounds of the outbox dispatcher.
+	outboxInterval = 5 * time.Second
+
+	// outboxBatchSize is the number of entries delivered in a round.
+	outboxBatchSize = 100
+
+	// outboxMaxAttempts is the number of attempts before an entry is marked as failed.
+	outboxMaxAttempts = 10
+
+	// outboxBackoff is the delay after the first failed attempt. It doubles on every attempt up to outboxMaxBackoff.
+	outboxBackoff    = 10 * time.Second
+	outboxMaxBackoff = time.Hour
+)
+
+// slackProposal is the payload of an entry notifying a proposal to Slack.
+type slackProposal struct {
+	ProposalID uint64 `json:"proposal_id"`
+}
+
+// getOutboxEntries returns the notifications of the block. They are delivered by the outbox dispatcher
+// only after the block is committed, so nothing is sent for a height which fails to commit or is rolled back.
+func (ex *Exporter) getOutboxEntries(block *tmctypes.ResultBlock, txs []*sdktypes.TxResponse, basic *mdschema.BasicData) ([]db.OutboxEntry, error) {
+	entries := make([]db.OutboxEntry, 0)
+	height := block.Block.Height
+	hash := block.BlockID.Hash.String()
+
+	add := func(kind, key string, payload interface{}) error {
+		bz, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s payload: %s", kind, err)
+		}
+		entries = append(entries, db.OutboxEntry{
+			ChainID:   block.Block.ChainID,
+			Height:    height,
+			BlockHash: hash,
+			Kind:      kind,
+			DedupKey:  fmt.Sprintf("%s/%d/%s/%s", kind, height, hash, key),
+			Payload:   bz,
+			Status:    db.OUTBOX_PENDING,
+		})
+		return nil
+	}
+
+	if ex.Config.Alarm.Switch {
+		for i, p := range getPushNotifications(txs) {
+			if err := add(OUTBOX_PUSH, fmt.Sprint(i), p); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if ex.Config.Slack.WebHook != "" {
+		ids := make(map[uint64]bool)
+		for _, p := range basic.Proposals {
+			ids[p.ID] = true
+		}
+		for _, d := range basic.Deposits {
+			ids[d.ProposalID] = true
+		}
+		for id := range ids {
+			if err := add(OUTBOX_SLACK_PROPOSAL, fmt.Sprint(id), slackProposal{ProposalID: id}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return entries, nil
+}
+
+// runOutboxDispatcher delivers committed outbox entries every outboxInterval until ctx is canceled.
+func (ex *Exporter) runOutboxDispatcher(ctx context.Context) {
+	var nof *notification.Notification
+	if ex.Config.Alarm.Switch {
+		nof = notification.NewNotification()
+	}
+
+	for sleep(ctx, outboxInterval) {
+		if err := ex.dispatchOutbox(ctx, nof); err != nil {
+			zap.S().Errorf("error - dispatch outbox: %s", err)
+		}
+	}
+}
+
+// dispatchOutbox drops entries of blocks replaced by rollback and delivers a batch of due entries.
+// A failed entry is retried with exponential backoff and marked as failed after outboxMaxAttempts.
+func (ex *Exporter) dispatchOutbox(ctx context.Context, nof *notification.Notification) error {
+	chainID := ex.Config.Chain.ChainID
+	chainInfoID := ex.ChainIDMap[chainID]
+
+	dropped, err := ex.DB.DropOrphanedOutboxEntries(chainInfoID, chainID)
+	if err != nil {
+		return fmt.Errorf("failed to drop orphaned outbox entries: %s", err)
+	}
+	if dropped > 0 {
+		zap.S().Infof("dropped %d outbox entries of rolled back blocks", dropped)
+	}
+
+	entries, err := ex.DB.GetDeliverableOutboxEntries(chainInfoID, chainID, outboxBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to get outbox entries: %s", err)
+	}
+
+	for i := range entries {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		e := &entries[i]
+		e.Attempts++
+		if err := ex.deliver(nof, e); err != nil {
+			e.LastError = err.Error()
+			if e.Attempts >= outboxMaxAttempts {
+				e.Status = db.OUTBOX_FAILED
+				zap.S().Errorf("outbox entry %d failed after %d attempts : %s", e.ID, e.Attempts, err)
+			} else {
+				e.NextAttemptAt = time.Now().Add(backoff(e.Attempts))
+			}
+		} else {
+			e.Status = db.OUTBOX_DONE
+		}
+
+		if err := ex.DB.UpdateOutboxEntry(e); err != nil {
+			return fmt.Errorf("failed to update outbox entry %d : %s", e.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// deliver delivers an entry according to its kind.
+func (ex *Exporter) deliver(nof *notification.Notification, e *db.OutboxEntry) error {
+	switch e.Kind {
+	case OUTBOX_PUSH:
+		if nof == nil {
+			return fmt.Errorf("push notification is disabled")
+		}
+		var p pushNotification
+		if err := json.Unmarshal(e.Payload, &p); err != nil {
+			return fmt.Errorf("failed to unmarshal payload: %s", err)
+		}
+		return ex.deliverPushNotification(nof, p)
+	case OUTBOX_SLACK_PROPOSAL:
+		var p slackProposal
+		if err := json.Unmarshal(e.Payload, &p); err != nil {
+			return fmt.Errorf("failed to unmarshal payload: %s", err)
+		}
+		return ex.ProposalNotificationToSlack(p.ProposalID)
+	default:
+		return fmt.Errorf("unknown outbox entry kind %s", e.Kind)
+	}
+}
+
+// backoff returns the delay before the next attempt after the given number of attempts.
+func backoff(attempts int) time.Duration {
+	d := outboxBackoff
+	for i := 1; i < attempts && d < outboxMaxBackoff; i++ {
+		d *= 2
+	}
+	if d > outboxMaxBackoff {
+		d = outboxMaxBackoff
+	}
+	return d
+}
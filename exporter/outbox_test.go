@@ -0,0 +1,65 @@
+package exporter
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/cosmostation/cosmostation-coreum/db"
+	"github.com/stretchr/testify/require"
+
+	mdschema "github.com/cosmostation/mintscan-database/schema"
+
+	tmctypes "github.com/cometbft/cometbft/rpc/core/types"
+	tmtypes "github.com/cometbft/cometbft/types"
+)
+
+func TestOutboxBackoff(t *testing.T) {
+	require.Equal(t, 10*time.Second, backoff(1))
+	require.Equal(t, 20*time.Second, backoff(2))
+	require.Equal(t, 80*time.Second, backoff(4))
+	require.Equal(t, time.Hour, backoff(20))
+}
+
+func TestGetOutboxEntries(t *testing.T) {
+	requireFixture(t)
+
+	webHook := ex.Config.Slack.WebHook
+	ex.Config.Slack.WebHook = "http://localhost"
+	defer func() { ex.Config.Slack.WebHook = webHook }()
+
+	newBlock := func(hash byte) *tmctypes.ResultBlock {
+		return &tmctypes.ResultBlock{
+			BlockID: tmtypes.BlockID{Hash: bytes.Repeat([]byte{hash}, 32)},
+			Block:   &tmtypes.Block{Header: tmtypes.Header{ChainID: "coreum-mainnet-1", Height: 100}},
+		}
+	}
+	basic := &mdschema.BasicData{
+		Proposals: []mdschema.Proposal{{ID: 1}},
+		Deposits:  []mdschema.Deposit{{ProposalID: 1}, {ProposalID: 2}},
+	}
+
+	// a proposal is notified once per block however many times it appears
+	entries, err := ex.getOutboxEntries(newBlock(1), nil, basic)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	keys := make(map[string]bool)
+	for _, e := range entries {
+		require.Equal(t, OUTBOX_SLACK_PROPOSAL, e.Kind)
+		require.Equal(t, int64(100), e.Height)
+		require.Equal(t, db.OUTBOX_PENDING, e.Status)
+		keys[e.DedupKey] = true
+	}
+
+	// the same block exported again has the same keys, while a block replacing it at the height does not
+	again, err := ex.getOutboxEntries(newBlock(1), nil, basic)
+	require.NoError(t, err)
+	for _, e := range again {
+		require.True(t, keys[e.DedupKey])
+	}
+	replaced, err := ex.getOutboxEntries(newBlock(2), nil, basic)
+	require.NoError(t, err)
+	for _, e := range replaced {
+		require.False(t, keys[e.DedupKey])
+	}
+}
@@ -0,0 +1,105 @@
+package exporter
+
+import (
+	"go.uber.org/zap"
+
+	// sdk
+	tmctypes "github.com/cometbft/cometbft/rpc/core/types"
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+)
+
+var (
+	// fetchWorkers is the number of goroutines querying and decoding blocks from the node at the same time.
+	fetchWorkers = 4
+
+	// prefetchDepth is how many heights may be fetched ahead of the height being committed.
+	prefetchDepth = 16
+)
+
+// fetchedBlock is a block, its transactions and its block results queried from the node for a single height.
+type fetchedBlock struct {
+	height int64
+	block  *tmctypes.ResultBlock
+	txs    []*sdktypes.TxResponse
+	// results is nil when the events of block results are not stored.
+	results *tmctypes.ResultBlockResults
+	err     error
+}
+
+// blockFetcher queries a block and its decoded transactions at the given height.
+// The height and the error of the fetched block are set by fetchBlocks.
+type blockFetcher func(height int64) (*fetchedBlock, error)
+
+// SetFetchOption sets the number of fetch workers and the prefetch depth of the sync pipeline.
+func SetFetchOption(workers, prefetch int) {
+	if workers > 0 {
+		fetchWorkers = workers
+	}
+	if prefetch > 0 {
+		prefetchDepth = prefetch
+	}
+	zap.S().Debugf("FetchWorkers : %d, PrefetchDepth : %d\n", fetchWorkers, prefetchDepth)
+}
+
+// fetchBlocks fetches heights from begin to end concurrently and yields them strictly in height order.
+// The returned channel is closed after end is delivered, after the first failed height is delivered,
+// or when stop is closed. Heights after a failed one are never delivered so that the caller leaves no gap.
+func fetchBlocks(stop <-chan struct{}, begin, end int64, fetch blockFetcher) <-chan *fetchedBlock {
+	out := make(chan *fetchedBlock)
+	pending := make(chan chan *fetchedBlock, prefetchDepth)
+
+	// dispatcher : reserves a slot for each height in order and hands the height to a worker
+	go func() {
+		defer close(pending)
+		sem := make(chan struct{}, fetchWorkers)
+		for h := begin; h <= end; h++ {
+			slot := make(chan *fetchedBlock, 1)
+			select {
+			case pending <- slot:
+			case <-stop:
+				return
+			}
+
+			select {
+			case sem <- struct{}{}:
+			case <-stop:
+				return
+			}
+
+			go func(h int64, slot chan<- *fetchedBlock) {
+				defer func() { <-sem }()
+				fb, err := fetch(h)
+				if fb == nil {
+					fb = new(fetchedBlock)
+				}
+				fb.height, fb.err = h, err
+				slot <- fb
+			}(h, slot)
+		}
+	}()
+
+	// sequencer : waits for the slots in the order they were reserved
+	go func() {
+		defer close(out)
+		for slot := range pending {
+			var fb *fetchedBlock
+			select {
+			case fb = <-slot:
+			case <-stop:
+				return
+			}
+
+			select {
+			case out <- fb:
+			case <-stop:
+				return
+			}
+
+			if fb.err != nil {
+				return
+			}
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,53 @@
+package exporter
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchBlocksInOrder(t *testing.T) {
+	SetFetchOption(8, 4)
+
+	fetch := func(height int64) (*fetchedBlock, error) {
+		time.Sleep(time.Duration(rand.Intn(5)) * time.Millisecond)
+		return &fetchedBlock{}, nil
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	expected := int64(1)
+	for fb := range fetchBlocks(stop, 1, 200, fetch) {
+		require.NoError(t, fb.err)
+		require.Equal(t, expected, fb.height)
+		expected++
+	}
+	require.Equal(t, int64(201), expected)
+}
+
+func TestFetchBlocksStopAtError(t *testing.T) {
+	SetFetchOption(8, 4)
+
+	failedHeight := int64(50)
+	fetch := func(height int64) (*fetchedBlock, error) {
+		if height == failedHeight {
+			return nil, fmt.Errorf("node is not available")
+		}
+		return &fetchedBlock{}, nil
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	var last *fetchedBlock
+	for fb := range fetchBlocks(stop, 1, 100, fetch) {
+		last = fb
+	}
+	require.NotNil(t, last)
+	require.Error(t, last.err)
+	require.Equal(t, failedHeight, last.height)
+}
@@ -1,19 +1,23 @@
 package exporter
 
 import (
+	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	tmctypes "github.com/cometbft/cometbft/rpc/core/types"
 	sdktypes "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmostation/cosmostation-coreum/custom"
+	"github.com/cosmostation/cosmostation-coreum/db"
+	"github.com/cosmostation/cosmostation-coreum/metrics"
+	"github.com/cosmostation/cosmostation-coreum/sink"
 	mdschema "github.com/cosmostation/mintscan-database/schema"
 	"go.uber.org/zap"
 )
 
-func (ex *Exporter) Refine(op int) error {
+func (ex *Exporter) Refine(ctx context.Context, op int) error {
 	var chainID string
-	aminoUnmarshal := custom.AppCodec.UnmarshalJSON
 
 	// 프로그램이 기동 되고, rawdb로부터 동기화 할 목표 높이
 	srcRawBlockHeight, err := ex.RawDB.GetLatestBlockHeight()
@@ -60,6 +64,7 @@ func (ex *Exporter) Refine(op int) error {
 			if err := ex.refineRawBlocks(rb); err != nil {
 				return err
 			}
+			ex.beat()
 		}
 	}
 
@@ -84,7 +89,11 @@ func (ex *Exporter) Refine(op int) error {
 			txs := make([]*sdktypes.TxResponse, len(ts))
 			for j, t := range ts {
 				tx := new(sdktypes.TxResponse)
-				if err := aminoUnmarshal(t.Chunk, tx); err != nil {
+				chunk, err := db.DecompressChunk(t.Chunk)
+				if err != nil {
+					return err
+				}
+				if err := custom.CodecAt(t.ChainID, t.Height).UnmarshalJSON(chunk, tx); err != nil {
 					return err
 				}
 				txs[j] = tx
@@ -95,17 +104,26 @@ func (ex *Exporter) Refine(op int) error {
 			if err := ex.refineRawTransactions(chainID, txs); err != nil {
 				return err
 			}
+			ex.beat()
 		}
 	}
 
+	return ex.refineRealTime(ctx)
+}
+
+// refineRealTime refines the heights after the refined ones from the node until ctx is canceled.
+func (ex *Exporter) refineRealTime(ctx context.Context) error {
 	// 실시간 refine
 	for {
-		if err := ex.refineSync(); err != nil {
+		if err := ex.refineSync(ctx); err != nil {
 			zap.S().Infof("error - sync blockchain: %s\n", err)
 		}
-		time.Sleep(2 * time.Second)
+		if !sleep(ctx, 2*time.Second) {
+			return nil
+		}
 	}
 }
+
 func (ex *Exporter) refineRawTransactions(chainID string, txs []*sdktypes.TxResponse) (err error) {
 	refineData := new(mdschema.RefineData)
 
@@ -131,7 +149,7 @@ func (ex *Exporter) refineRawTransactions(chainID string, txs []*sdktypes.TxResp
 	refineData.TMAs = ex.disassembleTransaction(txs)
 
 	if true {
-		return ex.DB.InsertRefineData(refineData)
+		return ex.Sink.WriteRefineData(refineData)
 	}
 	return fmt.Errorf("currently, disabled to store data into database\n")
 
@@ -162,36 +180,47 @@ func (ex *Exporter) refineRawBlocks(block []mdschema.RawBlock) (err error) {
 	}
 
 	if true {
-		return ex.DB.InsertRefineData(refineData)
+		return ex.Sink.WriteRefineData(refineData)
 	}
 	return fmt.Errorf("currently do not store any data\n")
 
 }
 
-func (ex *Exporter) refineSync() error {
-	// Query latest block height saved in database
-	dbHeight, err := ex.DB.GetLatestBlockHeight(ex.ChainIDMap[ex.Config.Chain.ChainID])
-	if dbHeight == -1 {
-		return fmt.Errorf("unexpected error in database: %s", err)
+func (ex *Exporter) refineSync(ctx context.Context) error {
+	// Query latest block height saved in the sink
+	dbHeight, err := ex.Sink.LatestHeight(sink.KindBasic, ex.Config.Chain.ChainID)
+	if err != nil {
+		return fmt.Errorf("unexpected error in sink: %s", err)
 	}
 
 	// Query latest block height on the active network
-	latestBlockHeight, err := ex.Client.RPC.GetLatestBlockHeight()
+	latestBlockHeight, err := ex.Client.GetLatestBlockHeight()
 	if latestBlockHeight == -1 {
+		metrics.NodeRPCErrors.WithLabelValues("GetLatestBlockHeight").Inc()
 		return fmt.Errorf("failed to query the latest block height on the active network: %s", err)
 	}
+	metrics.ChainTipHeight.WithLabelValues(ex.Config.Chain.ChainID).Set(float64(latestBlockHeight))
 
-	if dbHeight == 0 && initialHeight != 0 {
-		dbHeight = initialHeight - 1
-		zap.S().Info("initial Height set : ", initialHeight)
+	if dbHeight == 0 && ex.InitialHeight != 0 {
+		dbHeight = ex.InitialHeight - 1
+		zap.S().Info("initial Height set : ", ex.InitialHeight)
 	}
+	dbHeight = ex.continueLineage(dbHeight)
+	latestBlockHeight = ex.boundLineage(latestBlockHeight)
 
 	beginHeight := dbHeight
 
 	zap.S().Infof("dbHeight %d\n", dbHeight)
 
+	// controler bounds the transactions of a block queried concurrently
+	controler := make(chan struct{}, 60)
+	wg := new(sync.WaitGroup)
+
 	for i := beginHeight + 1; i <= latestBlockHeight; i++ {
-		block, err := ex.Client.RPC.GetBlock(i)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		block, err := ex.Client.GetBlock(i)
 		if err != nil {
 			return fmt.Errorf("failed to query block: %s", err)
 		}
@@ -211,7 +240,7 @@ func (ex *Exporter) refineSync() error {
 					wg.Done()
 				}()
 
-				txs[i], err = ex.Client.CliCtx.GetTx(hex)
+				txs[i], err = ex.Client.GetTx(custom.EncodingConfigAt(ex.Config.Chain.ChainID, block.Block.Height), hex)
 				if err != nil {
 					zap.S().Error("Error while getting tx ", hex)
 					retryFlag = true
@@ -234,6 +263,8 @@ func (ex *Exporter) refineSync() error {
 				return err
 			}
 		}
+		ex.lastBlock = blockRef{height: i, hash: block.BlockID.Hash.String()}
+		ex.beat()
 		zap.S().Infof("synced block %d/%d", i, latestBlockHeight)
 	}
 	return nil
@@ -260,5 +291,10 @@ func (ex *Exporter) refineRealTimeprocess(block *tmctypes.ResultBlock, txs []*sd
 		basic.TMAs = ex.disassembleTransaction(txs)
 	}
 
-	return ex.DB.InsertRefineRealTimeData(basic)
+	err = ex.Sink.WriteRefineRealTimeData(&db.ExportedData{BasicData: basic, ChainID: block.Block.ChainID})
+	if err != nil {
+		return err
+	}
+	metrics.ExportedHeight.WithLabelValues(ex.Config.Chain.ChainID, metrics.ModeRefine).Set(float64(block.Block.Height))
+	return nil
 }
@@ -0,0 +1,158 @@
+package exporter
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cosmostation/cosmostation-coreum/db"
+	"github.com/cosmostation/cosmostation-coreum/sink"
+	"go.uber.org/zap"
+
+	tmctypes "github.com/cometbft/cometbft/rpc/core/types"
+)
+
+const (
+	// REORG_HALT stops syncing when a block does not continue the stored chain.
+	REORG_HALT = "halt"
+	// REORG_ROLLBACK deletes the stored heights which are not on the chain served by the node and syncs them again.
+	REORG_ROLLBACK = "rollback"
+)
+
+var (
+	reorgPolicy      = REORG_HALT
+	maxRollbackDepth = int64(100)
+
+	errChainHalted = errors.New("sync halted by block hash mismatch")
+)
+
+// blockRef is the height and hash of the last block committed by sync.
+type blockRef struct {
+	height int64
+	hash   string
+}
+
+// SetReorgOption sets what to do on a block hash mismatch and how deep a rollback may go.
+func SetReorgOption(policy string, depth int64) {
+	switch policy {
+	case REORG_HALT, REORG_ROLLBACK:
+		reorgPolicy = policy
+	default:
+		zap.S().Infof("unknown reorg policy %s, will use %s", policy, reorgPolicy)
+	}
+	if depth > 0 {
+		maxRollbackDepth = depth
+	}
+	zap.S().Debugf("ReorgPolicy : %s, MaxRollbackDepth : %d\n", reorgPolicy, maxRollbackDepth)
+}
+
+// storedBlockHash returns the hash of the block stored at the height in the sink with the data the mode writes.
+func (ex *Exporter) storedBlockHash(op int, height int64) (string, error) {
+	if ex.lastBlock.height == height && ex.lastBlock.hash != "" {
+		return ex.lastBlock.hash, nil
+	}
+	if op == RAW_MODE {
+		return ex.Sink.BlockHash(sink.KindRaw, ex.Config.Chain.ChainID, height)
+	}
+	return ex.Sink.BlockHash(sink.KindBasic, ex.Config.Chain.ChainID, height)
+}
+
+// verifyParent compares LastBlockID of the block with the hash of the block stored at height-1.
+// It returns the stored hash and false on a mismatch. Blocks without a stored parent are not verified.
+func (ex *Exporter) verifyParent(op int, block *tmctypes.ResultBlock) (string, bool, error) {
+	height := block.Block.Height
+	if height == 1 || height == ex.InitialHeight {
+		return "", true, nil
+	}
+
+	stored, err := ex.storedBlockHash(op, height-1)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get stored block hash at %d : %s", height-1, err)
+	}
+	if stored == "" {
+		return "", true, nil
+	}
+
+	return stored, stored == block.Block.LastBlockID.Hash.String(), nil
+}
+
+// handleReorg records the incident and then either halts sync or rolls back
+// the stored heights that are not on the chain served by the node.
+// It always returns an error so that sync restarts from the height stored in the database.
+func (ex *Exporter) handleReorg(op int, block *tmctypes.ResultBlock, storedHash string) error {
+	height := block.Block.Height
+	reported := block.Block.LastBlockID.Hash.String()
+	zap.S().Errorf("block hash mismatch at %d : stored parent %s, reported parent %s", height, storedHash, reported)
+
+	incident := &db.BlockIncident{
+		ChainID:            ex.Config.Chain.ChainID,
+		Height:             height,
+		StoredParentHash:   storedHash,
+		ReportedParentHash: reported,
+		Action:             reorgPolicy,
+	}
+
+	var rollbackErr error
+	if reorgPolicy == REORG_ROLLBACK {
+		incident.ForkHeight, rollbackErr = ex.rollback(op, height-1)
+		if rollbackErr != nil {
+			incident.Action = REORG_HALT
+		}
+	}
+
+	// incidents are kept in database only, other sinks have the error log above
+	if ex.storesInDatabase() {
+		if err := ex.DB.InsertBlockIncident(incident); err != nil {
+			zap.S().Errorf("failed to insert block incident : %s", err)
+		}
+	}
+
+	if incident.Action == REORG_HALT {
+		if rollbackErr != nil {
+			return fmt.Errorf("%w at %d : %s", errChainHalted, height, rollbackErr)
+		}
+		return fmt.Errorf("%w at %d", errChainHalted, height)
+	}
+
+	return fmt.Errorf("rolled back heights from %d to %d", incident.ForkHeight+1, height-1)
+}
+
+// rollback finds the highest stored height which is still on the chain served by the node and deletes
+// every height above it in the sink. It returns the height of the common ancestor.
+func (ex *Exporter) rollback(op int, from int64) (int64, error) {
+	forkHeight, err := ex.findForkHeight(op, from)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := ex.Sink.Rollback(ex.Config.Chain.ChainID, forkHeight+1); err != nil {
+		return 0, err
+	}
+	ex.lastBlock = blockRef{}
+
+	zap.S().Infof("rolled back to height %d", forkHeight)
+	return forkHeight, nil
+}
+
+// findForkHeight walks back from the given height and returns the first height
+// where the stored block hash equals the block hash on the node.
+func (ex *Exporter) findForkHeight(op int, from int64) (int64, error) {
+	for h := from; h > 0 && h > from-maxRollbackDepth; h-- {
+		stored, err := ex.storedBlockHash(op, h)
+		if err != nil {
+			return 0, err
+		}
+		if stored == "" {
+			return h, nil
+		}
+
+		block, err := ex.Client.GetBlock(h)
+		if err != nil {
+			return 0, fmt.Errorf("failed to query block %d : %s", h, err)
+		}
+		if block.BlockID.Hash.String() == stored {
+			return h, nil
+		}
+	}
+
+	return 0, fmt.Errorf("no common ancestor within %d heights from %d", maxRollbackDepth, from)
+}
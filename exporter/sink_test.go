@@ -0,0 +1,89 @@
+package exporter
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cosmostation/cosmostation-coreum/app"
+	"github.com/cosmostation/cosmostation-coreum/sink"
+	"github.com/stretchr/testify/require"
+
+	mblconfig "github.com/cosmostation/mintscan-backend-library/config"
+
+	tmctypes "github.com/cometbft/cometbft/rpc/core/types"
+	tmtypes "github.com/cometbft/cometbft/types"
+)
+
+// stubBlock returns an empty block of the chain at the height whose parent is the stub block at height-1.
+func stubBlock(chainID string, height int64) *tmctypes.ResultBlock {
+	hash := func(h int64) []byte {
+		bz := sha256.Sum256([]byte(fmt.Sprintf("%s/%d", chainID, h)))
+		return bz[:]
+	}
+
+	return &tmctypes.ResultBlock{
+		BlockID: tmtypes.BlockID{Hash: hash(height)},
+		Block: &tmtypes.Block{
+			Header: tmtypes.Header{
+				ChainID:     chainID,
+				Height:      height,
+				Time:        time.Now().UTC(),
+				LastBlockID: tmtypes.BlockID{Hash: hash(height - 1)},
+			},
+			LastCommit: &tmtypes.Commit{},
+		},
+	}
+}
+
+func TestSyncToMemorySink(t *testing.T) {
+	const chainID = "coreum-stub-1"
+
+	cfg := new(mblconfig.Config)
+	cfg.Chain.ChainID = chainID
+
+	mem := sink.NewMemory()
+	var fetched atomic.Int64
+	mex := &Exporter{
+		App:           &app.App{Config: cfg},
+		Sink:          mem,
+		InitialHeight: 10,
+		propList:      make(map[uint64]struct{}),
+		fetch: func(ctx context.Context, height int64, withResults bool) (*fetchedBlock, error) {
+			fetched.Add(1)
+			return &fetchedBlock{block: stubBlock(chainID, height)}, nil
+		},
+	}
+	require.NoError(t, mex.syncTo(context.Background(), BASIC_MODE, 12))
+
+	basic, raw := mem.BasicData(), mem.RawData()
+	require.Len(t, basic, 3)
+	require.Len(t, raw, 3)
+	for i := range basic {
+		require.Equal(t, int64(10+i), basic[i].Block.Height)
+		require.Equal(t, int64(10+i), raw[i].Block.Height)
+	}
+
+	for _, kind := range []string{sink.KindBasic, sink.KindRaw} {
+		height, err := mem.LatestHeight(kind, chainID)
+		require.NoError(t, err)
+		require.Equal(t, int64(12), height)
+	}
+
+	// sync resumes from the heights of the sink, so stored heights are not fetched again
+	require.NoError(t, mex.syncTo(context.Background(), BASIC_MODE, 14))
+	require.Equal(t, int64(5), fetched.Load())
+	require.Len(t, mem.BasicData(), 5)
+	require.Len(t, mem.RawData(), 5)
+
+	// a block which does not continue the stored chain halts sync
+	mex.lastBlock = blockRef{}
+	mex.fetch = func(ctx context.Context, height int64, withResults bool) (*fetchedBlock, error) {
+		return &fetchedBlock{block: stubBlock("coreum-fork-1", height)}, nil
+	}
+	require.ErrorIs(t, mex.syncTo(context.Background(), BASIC_MODE, 15), errChainHalted)
+	require.Len(t, mem.BasicData(), 5)
+}
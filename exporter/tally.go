@@ -11,7 +11,7 @@ import (
 
 // updateProposal update proposal which is passed voting end time
 func (ex *Exporter) UpdateTally(id uint64) error {
-	tally, err := ex.Client.GRPC.GetProposalTallyResult_v1(context.Background(), id)
+	tally, err := ex.Client.GetProposalTallyResult_v1(context.Background(), id)
 	if err != nil {
 		zap.S().Errorf("failed to get tally on prop[%d]: %s", id, err)
 		return err
@@ -7,6 +7,7 @@ import (
 
 	// internal
 	"github.com/cosmostation/cosmostation-coreum/custom"
+	"github.com/cosmostation/cosmostation-coreum/db"
 
 	// core
 	mbltypes "github.com/cosmostation/mintscan-backend-library/types"
@@ -14,7 +15,9 @@ import (
 
 	// sdk
 	tmctypes "github.com/cometbft/cometbft/rpc/core/types"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
 	sdktypes "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
 )
 
 // getTxs decodes transactions in a block and return a format of database transaction.
@@ -25,11 +28,9 @@ func (ex *Exporter) getTxs(chainID string, list map[int64]*mdschema.Block, txRes
 		return txs, nil
 	}
 
-	codec := custom.AppCodec
-
 	for i := range txResp {
 
-		chunk, err := codec.MarshalJSON(txResp[i])
+		chunk, err := custom.CodecAt(chainID, txResp[i].Height).MarshalJSON(txResp[i])
 		if err != nil {
 			return txs, fmt.Errorf("failed to marshal tx : %s", err)
 		}
@@ -68,7 +69,7 @@ func (ex *Exporter) getRawTransactions(block *tmctypes.ResultBlock, txResps []*s
 	}
 
 	for i, txResp := range txResps {
-		chunk, err := custom.AppCodec.MarshalJSON(txResp)
+		chunk, err := custom.CodecAt(block.Block.ChainID, txResp.Height).MarshalJSON(txResp)
 		if err != nil {
 			log.Println(err)
 			return txChunk, fmt.Errorf("failed to marshal tx : %s", err)
@@ -82,6 +83,20 @@ func (ex *Exporter) getRawTransactions(block *tmctypes.ResultBlock, txResps []*s
 	return txChunk, nil
 }
 
+// decodeTx decodes the chunk of a stored transaction with the codec of its chain at its height.
+func (ex *Exporter) decodeTx(t *mdschema.Transaction) (*sdktypes.TxResponse, error) {
+	chunk, err := db.DecompressChunk(t.Chunk)
+	if err != nil {
+		return nil, err
+	}
+
+	txResp := new(sdktypes.TxResponse)
+	if err := custom.CodecAt(ex.ChainNumMap[t.ChainInfoID], t.Height).UnmarshalJSON(chunk, txResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tx %s: %s", t.Hash, err)
+	}
+	return txResp, nil
+}
+
 func (ex *Exporter) disassembleTransaction(txResps []*sdktypes.TxResponse) (uniqTransactionMessageAccounts []mdschema.TMA) {
 	if len(txResps) <= 0 {
 		return nil
@@ -98,7 +113,7 @@ func (ex *Exporter) disassembleTransaction(txResps []*sdktypes.TxResponse) (uniq
 
 			msgType, accounts := mbltypes.AccountExporterFromCosmosTxMsg(&msg)
 			// 어떤 msg 타입에 대해서도 signer를 이용해 accounts를 확보하면, 모든 메세지를 파싱할 수 있다.
-			signers := getSignerAddress(msg.GetSigners())
+			signers := ex.getSignerAddress(txResp.GetTx(), msg)
 			accounts = append(accounts, signers...)
 
 			for _, txParser := range custom.CustomTxParsers {
@@ -149,10 +164,33 @@ func parseTransactionMessageAccount(txHash string, msgAccount map[string]map[str
 	return tma
 }
 
-func getSignerAddress(accAddrs []sdktypes.AccAddress) (address []string) {
-	for _, addr := range accAddrs {
-		if addr.String() != "" {
-			address = append(address, addr.String())
+// getSignerAddress returns the signers of the message as account addresses of the network of the chain.
+// Messages decode their signers with the address prefix of the sdk config, which is the prefix of the first chain of the process,
+// so signers of a chain of another prefix are taken from the public keys of the signer infos of the transaction.
+func (ex *Exporter) getSignerAddress(tx sdktypes.Tx, msg sdktypes.Msg) (address []string) {
+	if ex.Network.AddressPrefix == sdktypes.GetConfig().GetBech32AccountAddrPrefix() {
+		for _, addr := range msg.GetSigners() {
+			if addr.String() != "" {
+				address = append(address, addr.String())
+			}
+		}
+		return address
+	}
+
+	t, ok := tx.(*txtypes.Tx)
+	if !ok || t.AuthInfo == nil {
+		return nil
+	}
+	for _, si := range t.AuthInfo.SignerInfos {
+		if si.PublicKey == nil {
+			continue
+		}
+		pk, ok := si.PublicKey.GetCachedValue().(cryptotypes.PubKey)
+		if !ok {
+			continue
+		}
+		if addr := ex.Network.AccAddress(pk.Address()); addr != "" {
+			address = append(address, addr)
 		}
 	}
 
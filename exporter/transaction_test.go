@@ -18,6 +18,8 @@ import (
 
 // TestGetTxsChunk decodes transactions in a block and return a format of database transaction.
 func TestGetTxsChunk(t *testing.T) {
+	requireFixture(t)
+
 	require.NotNil(t, ex.Client)
 	// 13030, 272247
 	// 122499 (multi msg type)
@@ -31,6 +33,8 @@ func TestGetTxsChunk(t *testing.T) {
 }
 
 func TestMsgParsing(t *testing.T) {
+	requireFixture(t)
+
 	require.NotNil(t, ex.Client)
 
 	block, txResps, err := ex.Client.RPC.GetBlockAndTxsFromNode(custom.AppCodec, 3344967)
@@ -72,6 +76,8 @@ func JSONStringUnmarshal(jsonString []string) error {
 }
 
 func TestGetMessage(t *testing.T) {
+	requireFixture(t)
+
 	// 13030, 272247
 	// 122499 (multi msg type)
 	block, err := ex.Client.RPC.GetBlock(970957)
@@ -143,6 +149,8 @@ func TestUnmarshalMessageString(t *testing.T) {
 // }
 
 func TestGetBlockandTx(t *testing.T) {
+	requireFixture(t)
+
 	h := int64(696591)
 	// b, txs, err := ex.Client.RPC.GetBlockAndTxsFromNode(custom.EncodingConfig.Marshaler, h)
 	b, txs, err := ex.Client.RPC.GetBlockAndTxsFromNode(custom.EncodingConfig.Codec, h)
@@ -156,6 +164,8 @@ func TestGetBlockandTx(t *testing.T) {
 }
 
 func TestICA(t *testing.T) {
+	requireFixture(t)
+
 	msgType := "ibcchannel/recv_packet"
 	beginID := int64(0)
 	// singleTx, err := ex.DB.GetTransactionByHash("EB5CCDC5CF23595FB62D2D4904A3EAF19814FF36C4164FD9CCDD4FF94EDA56A5")
@@ -0,0 +1,129 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cosmostation/cosmostation-coreum/db"
+	"go.uber.org/zap"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// txEventCursor is the name of the index cursor of the tx event indexer.
+	txEventCursor = "tx_event"
+
+	// txEventBatchSize is the number of transactions indexed at a time.
+	txEventBatchSize = 100
+)
+
+// indexTxEvents enables the tx event indexer in basic mode.
+var indexTxEvents = true
+
+// SetTxEventOption sets whether basic mode indexes the events of stored transactions.
+func SetTxEventOption(enabled bool) {
+	indexTxEvents = enabled
+	zap.S().Debugf("IndexTxEvents : %t\n", indexTxEvents)
+}
+
+// runTxEventIndexer indexes the events of stored transactions from the index cursor until ctx is canceled.
+// Transactions are indexed after they are committed, so a height exported again is indexed again by its new transaction ids.
+func (ex *Exporter) runTxEventIndexer(ctx context.Context) {
+	if !indexTxEvents {
+		return
+	}
+
+	for {
+		n, err := ex.indexTxEvents()
+		if err != nil {
+			zap.S().Errorf("error - index tx events: %s", err)
+		}
+		if n < txEventBatchSize || err != nil {
+			if !sleep(ctx, 2*time.Second) {
+				return
+			}
+		} else if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// indexTxEvents indexes a batch of transactions after the cursor and returns the number of transactions indexed.
+func (ex *Exporter) indexTxEvents() (int, error) {
+	chainInfoID := ex.ChainIDMap[ex.Config.Chain.ChainID]
+
+	pointer, err := ex.DB.GetIndexCursor(chainInfoID, txEventCursor)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get index cursor: %s", err)
+	}
+
+	txs, err := ex.DB.GetTransactionsAfter(chainInfoID, pointer, txEventBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get transactions: %s", err)
+	}
+	if len(txs) == 0 {
+		return 0, nil
+	}
+
+	events := make([]db.TxEvent, 0)
+	for i := range txs {
+		// a transaction which can not be decoded is skipped, so that it does not block the transactions after it
+		tx, err := ex.decodeTx(&txs[i])
+		if err != nil {
+			zap.S().Errorf("failed to index tx events of tx %d, skipped : %s", txs[i].ID, err)
+			continue
+		}
+		events = append(events, getTxEvents(chainInfoID, txs[i].ID, tx)...)
+	}
+
+	last := txs[len(txs)-1].ID
+	if err := ex.DB.SaveTxEvents(chainInfoID, txEventCursor, last, events); err != nil {
+		return 0, fmt.Errorf("failed to save tx events: %s", err)
+	}
+	zap.S().Debugf("indexed tx events to tx_id %d", last)
+
+	return len(txs), nil
+}
+
+// getTxEvents explodes the events of the logs of each message and the events of the transaction into rows.
+func getTxEvents(chainInfoID int, txID int64, tx *sdktypes.TxResponse) []db.TxEvent {
+	events := make([]db.TxEvent, 0)
+
+	for _, log := range tx.Logs {
+		for i, e := range log.Events {
+			for j, a := range e.Attributes {
+				events = append(events, db.TxEvent{
+					ChainInfoID: chainInfoID,
+					TxID:        txID,
+					MsgIndex:    int(log.MsgIndex),
+					EventIndex:  i,
+					Position:    j,
+					Height:      tx.Height,
+					Type:        e.Type,
+					Key:         a.Key,
+					Value:       a.Value,
+				})
+			}
+		}
+	}
+
+	for i, e := range tx.Events {
+		for j, a := range e.Attributes {
+			events = append(events, db.TxEvent{
+				ChainInfoID: chainInfoID,
+				TxID:        txID,
+				MsgIndex:    -1,
+				EventIndex:  i,
+				Position:    j,
+				Height:      tx.Height,
+				Type:        e.Type,
+				Key:         a.Key,
+				Value:       a.Value,
+			})
+		}
+	}
+
+	return events
+}
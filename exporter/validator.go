@@ -8,7 +8,6 @@ import (
 	"go.uber.org/zap"
 
 	// mbl
-	"github.com/cosmostation/cosmostation-coreum/custom"
 	mbltypes "github.com/cosmostation/mintscan-backend-library/types"
 	mdschema "github.com/cosmostation/mintscan-database/schema"
 
@@ -19,9 +18,10 @@ import (
 	tmctypes "github.com/cometbft/cometbft/rpc/core/types"
 )
 
-var (
-	powerReduction = new(big.Float).SetInt(custom.PowerReduction.BigInt())
-)
+// powerReduction returns the power reduction of the network of the chain as a float.
+func (ex *Exporter) powerReduction() *big.Float {
+	return new(big.Float).SetInt(ex.Network.PowerReduction.BigInt())
+}
 
 // getPowerEventHistory returns voting power event history of validators by decoding transactions in a block.
 func (ex *Exporter) getPowerEventHistoryNew( /*block *tmctypes.ResultBlock,*/ txResp []*sdktypes.TxResponse) ([]mdschema.PowerEventHistory, error) {
@@ -66,7 +66,7 @@ func (ex *Exporter) getPowerEventHistoryNew( /*block *tmctypes.ResultBlock,*/ tx
 
 				// newVotingPowerAmount := float64(m.Value.Amount.Quo(custom.PowerReduction).Int64())
 				amount := new(big.Float).SetInt(m.Value.Amount.BigInt())
-				newVotingPowerAmount, _ := new(big.Float).Quo(amount, powerReduction).Float64()
+				newVotingPowerAmount, _ := new(big.Float).Quo(amount, ex.powerReduction()).Float64()
 
 				peh := &mdschema.PowerEventHistory{
 					Height:               tx.Height,
@@ -86,7 +86,7 @@ func (ex *Exporter) getPowerEventHistoryNew( /*block *tmctypes.ResultBlock,*/ tx
 
 				// newVotingPowerAmount := float64(m.Amount.Amount.Quo(custom.PowerReduction).Int64())
 				amount := new(big.Float).SetInt(m.Amount.Amount.BigInt())
-				newVotingPowerAmount, _ := new(big.Float).Quo(amount, powerReduction).Float64()
+				newVotingPowerAmount, _ := new(big.Float).Quo(amount, ex.powerReduction()).Float64()
 
 				peh := &mdschema.PowerEventHistory{
 					Height:               tx.Height,
@@ -106,7 +106,7 @@ func (ex *Exporter) getPowerEventHistoryNew( /*block *tmctypes.ResultBlock,*/ tx
 
 				// newVotingPowerAmount := float64(m.Amount.Amount.Quo(custom.PowerReduction).Int64())
 				amount := new(big.Float).SetInt(m.Amount.Amount.BigInt())
-				newVotingPowerAmount, _ := new(big.Float).Quo(amount, powerReduction).Float64()
+				newVotingPowerAmount, _ := new(big.Float).Quo(amount, ex.powerReduction()).Float64()
 
 				peh := &mdschema.PowerEventHistory{
 					Height:               tx.Height,
@@ -126,7 +126,7 @@ func (ex *Exporter) getPowerEventHistoryNew( /*block *tmctypes.ResultBlock,*/ tx
 
 				// newVotingPowerAmount := float64(m.Amount.Amount.Quo(custom.PowerReduction).Int64())
 				amount := new(big.Float).SetInt(m.Amount.Amount.BigInt())
-				newVotingPowerAmount, _ := new(big.Float).Quo(amount, powerReduction).Float64()
+				newVotingPowerAmount, _ := new(big.Float).Quo(amount, ex.powerReduction()).Float64()
 
 				// destination (add power)
 				dpeh := &mdschema.PowerEventHistory{
@@ -165,17 +165,17 @@ func (ex *Exporter) getPowerEventHistoryNew( /*block *tmctypes.ResultBlock,*/ tx
 	return powerEventHistory, nil
 }
 
-// getValidatorsUptime has three slices
+// getValidatorsUptime has two slices
 // missDetail gets every block
 func (ex *Exporter) getValidatorsUptime(prevBlock *tmctypes.ResultBlock,
-	block *tmctypes.ResultBlock, vals *tmctypes.ResultValidators) ([]mdschema.Miss, []mdschema.Miss, []mdschema.MissDetail, error) {
+	block *tmctypes.ResultBlock, vals *tmctypes.ResultValidators) ([]mdschema.Miss, []mdschema.MissDetail, error) {
 
 	miss := make([]mdschema.Miss, 0)
-	accumMiss := make([]mdschema.Miss, 0)
 	missDetail := make([]mdschema.MissDetail, 0)
 
 	// MissDetailInfo saves every missing block of validators
 	// while MissInfo saves ranges of missing blocks of validators.
+	// A miss of a single height is merged into the range of the previous height when it is stored.
 	for i, val := range vals.Validators {
 		// First block doesn't have any signatures from last commit
 		if len(block.Block.LastCommit.Signatures) == 0 {
@@ -193,45 +193,18 @@ func (ex *Exporter) getValidatorsUptime(prevBlock *tmctypes.ResultBlock,
 
 			missDetail = append(missDetail, m)
 
-			// Set initial variables
-			startHeight := prevBlock.Block.Header.Height
-			endHeight := prevBlock.Block.Header.Height
-			missingCount := int64(1)
-
-			// Query if a validator hash missed previous block.
-			prevMiss := ex.DB.QueryMissingPreviousBlock(val.Address.String(), endHeight-int64(1))
-
-			// Validator hasn't missed previous block.
-			if prevMiss.Address == "" {
-				m := mdschema.Miss{
-					Address:      val.Address.String(),
-					StartHeight:  startHeight,
-					EndHeight:    endHeight,
-					MissingCount: missingCount,
-					StartTime:    prevBlock.Block.Header.Time,
-					EndTime:      prevBlock.Block.Header.Time,
-				}
-
-				miss = append(miss, m)
-			}
-
-			// Validator has missed previous block.
-			if prevMiss.Address != "" {
-				m := mdschema.Miss{
-					Address:      prevMiss.Address,
-					StartHeight:  prevMiss.StartHeight,
-					EndHeight:    prevMiss.EndHeight + int64(1),
-					MissingCount: prevMiss.MissingCount + int64(1),
-					StartTime:    prevMiss.StartTime,
-					EndTime:      prevBlock.Block.Header.Time,
-				}
-
-				accumMiss = append(accumMiss, m)
-			}
+			miss = append(miss, mdschema.Miss{
+				Address:      val.Address.String(),
+				StartHeight:  prevBlock.Block.Header.Height,
+				EndHeight:    prevBlock.Block.Header.Height,
+				MissingCount: 1,
+				StartTime:    prevBlock.Block.Header.Time,
+				EndTime:      prevBlock.Block.Header.Time,
+			})
 		}
 	}
 
-	return miss, accumMiss, missDetail, nil
+	return miss, missDetail, nil
 }
 
 // getEvidence provides evidence of malicious wrong-doing by validators.
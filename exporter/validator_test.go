@@ -15,10 +15,13 @@ var (
 )
 
 func TestValidatorRank(t *testing.T) {
+	requireFixture(t)
+
 	ex.saveValidators()
 }
 
 func TestGetPowerEventHistory(t *testing.T) {
+	requireFixture(t)
 
 	b, err := ex.Client.RPC.GetBlock(5103)
 	require.NoError(t, err)
@@ -0,0 +1,86 @@
+// Package health serves liveness and readiness probes of the binaries in this repository.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// checkTimeout bounds the time a single check may take.
+var checkTimeout = 5 * time.Second
+
+// Check returns an error when the component it checks is not healthy.
+type Check func(ctx context.Context) error
+
+// Checks maps the name of each check to the check.
+type Checks map[string]Check
+
+// Bound returns a check which runs fn and fails with the error of ctx when ctx is done before fn returns,
+// so that a check of a client which does not take a context still ends within the timeout of the probe.
+// fn keeps running in the background until it returns.
+func Bound(fn func() error) Check {
+	return func(ctx context.Context) error {
+		done := make(chan error, 1)
+		go func() {
+			done <- fn()
+		}()
+
+		select {
+		case err := <-done:
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Result is the response body of a probe.
+type Result struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// Handler runs every check and responds 200 when all of them pass and 503 otherwise.
+func Handler(checks Checks) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		result, ok := Run(r.Context(), checks)
+
+		rw.Header().Set("Content-Type", "application/json")
+		if !ok {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(rw).Encode(result)
+	}
+}
+
+// Run runs the checks in order of their names and reports whether all of them passed.
+func Run(ctx context.Context, checks Checks) (Result, bool) {
+	names := make([]string, 0, len(checks))
+	for name := range checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := Result{Status: "ok", Checks: make(map[string]string, len(checks))}
+	ok := true
+	for _, name := range names {
+		cctx, cancel := context.WithTimeout(ctx, checkTimeout)
+		err := checks[name](cctx)
+		cancel()
+
+		if err != nil {
+			result.Checks[name] = err.Error()
+			ok = false
+			continue
+		}
+		result.Checks[name] = "ok"
+	}
+	if !ok {
+		result.Status = "unavailable"
+	}
+
+	return result, ok
+}
@@ -0,0 +1,60 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	pass := func(ctx context.Context) error { return nil }
+	fail := func(ctx context.Context) error { return errors.New("connection refused") }
+
+	tests := []struct {
+		name   string
+		checks Checks
+		code   int
+		status string
+	}{
+		{"no checks", Checks{}, http.StatusOK, "ok"},
+		{"all pass", Checks{"db": pass, "node": pass}, http.StatusOK, "ok"},
+		{"one fails", Checks{"db": pass, "node": fail}, http.StatusServiceUnavailable, "unavailable"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			Handler(tc.checks)(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			require.Equal(t, tc.code, rec.Code)
+
+			var result Result
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&result))
+			require.Equal(t, tc.status, result.Status)
+			for name := range tc.checks {
+				require.Contains(t, result.Checks, name)
+			}
+		})
+	}
+}
+
+func TestBound(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	hang := Bound(func() error {
+		<-release
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, hang(ctx), context.DeadlineExceeded)
+
+	require.EqualError(t, Bound(func() error { return errors.New("connection refused") })(context.Background()), "connection refused")
+}
@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "chain_exporter"
+
+// Modes of the chain-exporter used as the mode label of ExportedHeight.
+const (
+	ModeBasic  = "basic"
+	ModeRaw    = "raw"
+	ModeRefine = "refine"
+)
+
+// Stages of processing a height used as the stage label of StageDuration.
+const (
+	StageGetBlock            = "getBlock"
+	StageGetTxs              = "getTxs"
+	StageGetGovernance       = "getGovernance"
+	StageGetValidatorsUptime = "getValidatorsUptime"
+	StageInsertExportedData  = "InsertExportedData"
+)
+
+var (
+	// ChainTipHeight is the latest block height reported by the node per chain.
+	ChainTipHeight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "chain_tip_height",
+		Help:      "Latest block height reported by the node.",
+	}, []string{"chain_id"})
+
+	// ExportedHeight is the last height committed to database per chain and mode.
+	ExportedHeight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "exported_height",
+		Help:      "Last block height committed to database.",
+	}, []string{"chain_id", "mode"})
+
+	// StageDuration is the latency of each stage of processing a height.
+	StageDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "stage_duration_seconds",
+		Help:      "Latency of each stage of processing a height.",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 2, 16),
+	}, []string{"stage"})
+
+	// NodeRPCErrors counts failed requests to the node per method.
+	NodeRPCErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "node_rpc_errors_total",
+		Help:      "Number of failed requests to the node.",
+	}, []string{"method"})
+
+	// CatchingUp is 1 while the exporter is behind the chain tip of the chain, 0 otherwise.
+	CatchingUp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "catching_up",
+		Help:      "1 if the exporter is catching up with the chain tip, 0 otherwise.",
+	}, []string{"chain_id"})
+)
+
+func init() {
+	prometheus.MustRegister(
+		ChainTipHeight,
+		ExportedHeight,
+		StageDuration,
+		NodeRPCErrors,
+		CatchingUp,
+	)
+}
+
+// ObserveStage records the time elapsed since begin as the latency of the stage.
+func ObserveStage(stage string, begin time.Time) {
+	StageDuration.WithLabelValues(stage).Observe(time.Since(begin).Seconds())
+}
+
+// SetCatchingUp sets the catching up flag of the chain.
+func SetCatchingUp(chainID string, catchingUp bool) {
+	if catchingUp {
+		CatchingUp.WithLabelValues(chainID).Set(1)
+		return
+	}
+	CatchingUp.WithLabelValues(chainID).Set(0)
+}
+
+// Handler returns the HTTP handler serving every registered metric.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
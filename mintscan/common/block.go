@@ -27,7 +27,7 @@ func GetBlock(a *app.App) http.HandlerFunc {
 		}
 
 		// get block
-		block, err := a.Client.RPC.Block(context.Background(), &height)
+		block, err := a.Client.Block(context.Background(), &height)
 		if err != nil {
 			zap.S().Debug("failed to parse HTTP args ", zap.Error(err))
 			errors.ErrServerUnavailable(rw, http.StatusInternalServerError)
@@ -0,0 +1,140 @@
+package common
+
+import (
+	"net/http"
+
+	"github.com/cosmostation/cosmostation-coreum/app"
+	"github.com/cosmostation/cosmostation-coreum/db"
+	"github.com/cosmostation/cosmostation-coreum/errors"
+	"go.uber.org/zap"
+)
+
+// BlockEvent is an event of block results with its attributes in the order they were emitted.
+// TxIndex is the index of the transaction in the block for events of tx phase, -1 otherwise.
+type BlockEvent struct {
+	Height     int64                 `json:"height"`
+	Phase      string                `json:"phase"`
+	TxIndex    int                   `json:"tx_index"`
+	EventIndex int                   `json:"event_index"`
+	Type       string                `json:"type"`
+	Attributes []BlockEventAttribute `json:"attributes"`
+}
+
+// BlockEventAttribute is an attribute of a block event.
+type BlockEventAttribute struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// BlockEvents is a page of block events. Next is the value of before for the next page, 0 on the last page.
+type BlockEvents struct {
+	Events []BlockEvent `json:"events"`
+	Next   int64        `json:"next"`
+}
+
+// blockEventKey identifies an event of block results.
+type blockEventKey struct {
+	height     int64
+	phase      string
+	txIndex    int
+	eventIndex int
+}
+
+// SearchBlockEvents returns the begin-block, end-block and tx events of block results matching the event parameter,
+// e.g. /block_events/search?event=transfer.recipient=core1...&limit=20.
+// Pages are requested with before, the lowest height of the previous page, and always hold every matching event of their heights.
+func SearchBlockEvents(a *app.App) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+
+		if q.Get("event") == "" {
+			errors.ErrRequiredParam(rw, http.StatusBadRequest, "event is required")
+			return
+		}
+		p, err := parseEventPredicate(q.Get("event"))
+		if err != nil {
+			errors.ErrInvalidParam(rw, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		before, limit, err := parsePage(q.Get("before"), q.Get("limit"))
+		if err != nil {
+			errors.ErrInvalidParam(rw, http.StatusBadRequest, err.Error())
+			return
+		}
+		to := int64(0)
+		if before > 0 {
+			to = before - 1
+		}
+
+		chainIDs := a.ChainIDs()
+		events, err := a.DB.GetBlockEvents(chainIDs, p.Type, p.Key, p.Value, 0, to, limit)
+		full := err == nil && len(events) == limit
+		if full {
+			var height int64
+			events, height = trimBlockEventPage(events)
+			// a height with more matching events than the limit is returned in full
+			if height > 0 {
+				events, err = a.DB.GetBlockEvents(chainIDs, p.Type, p.Key, p.Value, height, height, 0)
+			}
+		}
+		if err != nil {
+			zap.S().Debug("failed to get block events ", zap.Error(err))
+			errors.ErrServerUnavailable(rw, http.StatusInternalServerError)
+			return
+		}
+
+		attrs, err := a.DB.GetBlockEventAttributes(events)
+		if err != nil {
+			zap.S().Debug("failed to get block event attributes ", zap.Error(err))
+			errors.ErrServerUnavailable(rw, http.StatusInternalServerError)
+			return
+		}
+
+		result := BlockEvents{Events: newBlockEvents(events, attrs)}
+		if full && len(events) > 0 {
+			result.Next = events[len(events)-1].Height
+		}
+
+		respond(rw, result)
+		return
+	}
+}
+
+// trimBlockEventPage drops the events of the lowest height of a full page, since the limit may have cut them off,
+// so that the next page starts with that height. When every event of the page is of a single height,
+// the events are kept and the height is returned to be queried in full.
+func trimBlockEventPage(events []db.BlockEvent) ([]db.BlockEvent, int64) {
+	last := events[len(events)-1].Height
+	for i := len(events) - 1; i >= 0; i-- {
+		if events[i].Height != last {
+			return events[:i+1], 0
+		}
+	}
+	return events, last
+}
+
+// newBlockEvents attaches the attributes to the events they belong to.
+func newBlockEvents(events []db.BlockEvent, attrs []db.BlockEventAttribute) []BlockEvent {
+	byEvent := make(map[blockEventKey][]BlockEventAttribute, len(events))
+	for _, a := range attrs {
+		k := blockEventKey{a.Height, a.Phase, a.TxIndex, a.EventIndex}
+		byEvent[k] = append(byEvent[k], BlockEventAttribute{Key: a.Key, Value: a.Value})
+	}
+
+	result := make([]BlockEvent, len(events))
+	for i, e := range events {
+		result[i] = BlockEvent{
+			Height:     e.Height,
+			Phase:      e.Phase,
+			TxIndex:    e.TxIndex,
+			EventIndex: e.EventIndex,
+			Type:       e.Type,
+			Attributes: byEvent[blockEventKey{e.Height, e.Phase, e.TxIndex, e.EventIndex}],
+		}
+		if result[i].Attributes == nil {
+			result[i].Attributes = make([]BlockEventAttribute, 0)
+		}
+	}
+	return result
+}
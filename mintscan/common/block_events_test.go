@@ -0,0 +1,45 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/cosmostation/cosmostation-coreum/db"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrimBlockEventPage(t *testing.T) {
+	events := []db.BlockEvent{{Height: 12}, {Height: 11}, {Height: 10}, {Height: 10}}
+	trimmed, height := trimBlockEventPage(events)
+	require.Equal(t, events[:2], trimmed)
+	require.Equal(t, int64(0), height)
+
+	// a single height is queried in full
+	events = []db.BlockEvent{{Height: 10}, {Height: 10}}
+	trimmed, height = trimBlockEventPage(events)
+	require.Equal(t, events, trimmed)
+	require.Equal(t, int64(10), height)
+}
+
+func TestNewBlockEvents(t *testing.T) {
+	events := []db.BlockEvent{
+		{ChainID: "test-1", Height: 10, Phase: db.PHASE_BEGIN_BLOCK, TxIndex: -1, EventIndex: 0, Type: "transfer"},
+		{ChainID: "test-1", Height: 10, Phase: db.PHASE_TX, TxIndex: 0, EventIndex: 1, Type: "transfer"},
+	}
+	attrs := []db.BlockEventAttribute{
+		{ChainID: "test-1", Height: 10, Phase: db.PHASE_BEGIN_BLOCK, TxIndex: -1, EventIndex: 0, Position: 0, Type: "transfer", Key: "recipient", Value: "a"},
+		{ChainID: "test-1", Height: 10, Phase: db.PHASE_BEGIN_BLOCK, TxIndex: -1, EventIndex: 0, Position: 1, Type: "transfer", Key: "amount", Value: "1"},
+	}
+
+	result := newBlockEvents(events, attrs)
+	require.Len(t, result, 2)
+	require.Equal(t, BlockEvent{
+		Height:     10,
+		Phase:      db.PHASE_BEGIN_BLOCK,
+		TxIndex:    -1,
+		EventIndex: 0,
+		Type:       "transfer",
+		Attributes: []BlockEventAttribute{{Key: "recipient", Value: "a"}, {Key: "amount", Value: "1"}},
+	}, result[0])
+	require.Empty(t, result[1].Attributes)
+	require.NotNil(t, result[1].Attributes)
+}
@@ -12,11 +12,14 @@ type NodeInfo struct {
 	Network             string `json:"network"`
 	LatestBlockHeight   int64  `json:"latest_block_height"`
 	EarliestBlockHeight int64  `json:"earliest_block_height"`
+
+	// ChainLineage is the chain-ids the network was launched with, so that heights before the last hard fork are attributed to their chain-id.
+	ChainLineage []app.ChainSegment `json:"chain_lineage,omitempty"`
 }
 
 func GetBlocksLatest(a *app.App) http.HandlerFunc {
 	return func(rw http.ResponseWriter, r *http.Request) {
-		status, err := a.Client.RPC.GetStatus()
+		status, err := a.Client.GetStatus()
 		if err != nil {
 			zap.S().Debug("failed to get network status", zap.Error(err))
 			errors.ErrServerUnavailable(rw, http.StatusInternalServerError)
@@ -26,6 +29,7 @@ func GetBlocksLatest(a *app.App) http.HandlerFunc {
 			Network:             status.NodeInfo.Network,
 			LatestBlockHeight:   status.SyncInfo.LatestBlockHeight,
 			EarliestBlockHeight: status.SyncInfo.EarliestBlockHeight,
+			ChainLineage:        a.ChainLineage(),
 		}
 
 		respond(rw, nodeInfo)
@@ -27,7 +27,7 @@ func GetBlockResults(a *app.App) http.HandlerFunc {
 		}
 
 		// get block results
-		blockResults, err := a.Client.RPC.BlockResults(context.Background(), &height)
+		blockResults, err := a.Client.BlockResults(context.Background(), &height)
 		if err != nil {
 			zap.S().Debug("failed to parse HTTP args ", zap.Error(err))
 			errors.ErrServerUnavailable(rw, http.StatusInternalServerError)
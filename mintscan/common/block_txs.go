@@ -33,7 +33,7 @@ func GetBlockTxs(a *app.App) http.HandlerFunc {
 
 		bt := BlockTxs{}
 		// get block and transactions
-		block, txs, err := a.Client.RPC.GetBlockAndTxsFromNode(custom.AppCodec, height)
+		block, txs, err := a.Client.GetBlockAndTxsFromNode(custom.EncodingConfigAt(a.Config.Chain.ChainID, height), height)
 		if err != nil {
 			zap.S().Debug("failed to parse HTTP args ", zap.Error(err))
 			errors.ErrServerUnavailable(rw, http.StatusInternalServerError)
@@ -51,7 +51,7 @@ func GetBlockTxs(a *app.App) http.HandlerFunc {
 		bt.Block = blockChunk
 
 		for i := range txs {
-			raw, err := custom.AppCodec.MarshalJSON(txs[i])
+			raw, err := custom.CodecAt(a.Config.Chain.ChainID, height).MarshalJSON(txs[i])
 			if err != nil {
 				zap.S().Debugf("failed to marshal tx hash = %s, err = %s\n", txs[i].TxHash, zap.Error(err))
 				errors.ErrServerUnavailable(rw, http.StatusInternalServerError)
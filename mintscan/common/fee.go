@@ -0,0 +1,134 @@
+package common
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cosmostation/cosmostation-coreum/app"
+	"github.com/cosmostation/cosmostation-coreum/errors"
+	"go.uber.org/zap"
+
+	sdktypes "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// dateLayout is the layout of dates in query parameters and responses.
+	dateLayout = "2006-01-02"
+	// defaultFeeDays is the number of days returned when from is not given.
+	defaultFeeDays = 30
+	// maxFeeDays is the maximum number of days of a query.
+	maxFeeDays = 366
+)
+
+// DailyFee is the total fee of a denom paid on a day in UTC.
+type DailyFee struct {
+	Date   string `json:"date"`
+	Denom  string `json:"denom"`
+	Amount string `json:"amount"`
+}
+
+// DenomFee is the total fee of a denom paid in the days of a query.
+type DenomFee struct {
+	Denom  string `json:"denom"`
+	Amount string `json:"amount"`
+}
+
+// GetDailyFees returns the total fee per day and denom, e.g. /fees/daily?from=2023-10-01&to=2023-10-31&denom=ucore.
+// Both from and to are inclusive, to defaults to today and from to 30 days before to.
+func GetDailyFees(a *app.App) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+
+		from, to, err := parseDays(q.Get("from"), q.Get("to"), time.Now())
+		if err != nil {
+			errors.ErrInvalidParam(rw, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		dfs, err := a.DB.GetDailyFees(from, to, q.Get("denom"))
+		if err != nil {
+			zap.S().Debug("failed to get daily fees ", zap.Error(err))
+			errors.ErrServerUnavailable(rw, http.StatusInternalServerError)
+			return
+		}
+
+		result := make([]DailyFee, len(dfs))
+		for i, df := range dfs {
+			result[i] = DailyFee{
+				Date:   df.Timestamp.UTC().Format(dateLayout),
+				Denom:  df.Denom,
+				Amount: df.Amount,
+			}
+		}
+
+		respond(rw, result)
+		return
+	}
+}
+
+// GetDenomFees returns the total fee per denom in the days of the query, e.g. /fees/denoms?from=2023-10-01&to=2023-10-31.
+// The days are given as GetDailyFees.
+func GetDenomFees(a *app.App) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+
+		from, to, err := parseDays(q.Get("from"), q.Get("to"), time.Now())
+		if err != nil {
+			errors.ErrInvalidParam(rw, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		dfs, err := a.DB.GetDailyFees(from, to, "")
+		if err != nil {
+			zap.S().Debug("failed to get daily fees ", zap.Error(err))
+			errors.ErrServerUnavailable(rw, http.StatusInternalServerError)
+			return
+		}
+
+		total := sdktypes.NewCoins()
+		for _, df := range dfs {
+			amount, ok := sdktypes.NewIntFromString(df.Amount)
+			if !ok {
+				zap.S().Debugf("failed to parse daily fee amount %s of %s", df.Amount, df.Denom)
+				errors.ErrFailedConversion(rw, http.StatusInternalServerError)
+				return
+			}
+			total = total.Add(sdktypes.NewCoin(df.Denom, amount))
+		}
+
+		result := make([]DenomFee, len(total))
+		for i, c := range total {
+			result[i] = DenomFee{Denom: c.Denom, Amount: c.Amount.String()}
+		}
+
+		respond(rw, result)
+		return
+	}
+}
+
+// parseDays parses the inclusive dates from and to into the half-open range of days [from, to+1day).
+func parseDays(fromStr, toStr string, now time.Time) (from, to time.Time, err error) {
+	to = now.UTC().Truncate(24 * time.Hour)
+	if toStr != "" {
+		if to, err = time.Parse(dateLayout, toStr); err != nil {
+			return from, to, fmt.Errorf("invalid to %q", toStr)
+		}
+	}
+
+	from = to.AddDate(0, 0, -(defaultFeeDays - 1))
+	if fromStr != "" {
+		if from, err = time.Parse(dateLayout, fromStr); err != nil {
+			return from, to, fmt.Errorf("invalid from %q", fromStr)
+		}
+	}
+
+	to = to.AddDate(0, 0, 1)
+	if !from.Before(to) {
+		return from, to, fmt.Errorf("from %q is after to %q", fromStr, toStr)
+	}
+	if to.Sub(from) > maxFeeDays*24*time.Hour {
+		return from, to, fmt.Errorf("range must not exceed %d days", maxFeeDays)
+	}
+	return from, to, nil
+}
@@ -0,0 +1,28 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDays(t *testing.T) {
+	now := time.Date(2023, 10, 31, 15, 0, 0, 0, time.UTC)
+
+	from, to, err := parseDays("", "", now)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC), from)
+	require.Equal(t, time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC), to)
+
+	from, to, err = parseDays("2023-10-01", "2023-10-01", now)
+	require.NoError(t, err)
+	require.Equal(t, 24*time.Hour, to.Sub(from))
+
+	_, _, err = parseDays("2023-10-02", "2023-10-01", now)
+	require.Error(t, err)
+	_, _, err = parseDays("2022-01-01", "2023-10-01", now)
+	require.Error(t, err)
+	_, _, err = parseDays("10/01/2023", "", now)
+	require.Error(t, err)
+}
@@ -0,0 +1,136 @@
+package common
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cosmostation/cosmostation-coreum/app"
+	"github.com/cosmostation/cosmostation-coreum/db"
+	"github.com/cosmostation/cosmostation-coreum/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultGasSummaryDays is the number of days summarized when days is not given.
+	defaultGasSummaryDays = 7
+	// maxGasSummaryDays is the maximum number of days summarized.
+	maxGasSummaryDays = 90
+)
+
+// GasStat is the distribution of gas used by transactions of a message type in an hour or a day.
+// FeeAvg is the average fee in FeeDenom, empty when no transaction of the bucket paid the fee in FeeDenom.
+type GasStat struct {
+	Period       string    `json:"period"`
+	BucketStart  time.Time `json:"bucket_start"`
+	MsgType      string    `json:"msg_type"`
+	NumTxs       int64     `json:"num_txs"`
+	GasUsedP50   int64     `json:"gas_used_p50"`
+	GasUsedP95   int64     `json:"gas_used_p95"`
+	GasUsedAvg   int64     `json:"gas_used_avg"`
+	GasWantedAvg int64     `json:"gas_wanted_avg"`
+	FeeAvg       string    `json:"fee_avg"`
+	FeeDenom     string    `json:"fee_denom"`
+}
+
+// GasSummary is the distribution of gas used by transactions of a message type in the last days.
+type GasSummary struct {
+	MsgType      string `json:"msg_type"`
+	NumTxs       int64  `json:"num_txs"`
+	GasUsedP50   int64  `json:"gas_used_p50"`
+	GasUsedP95   int64  `json:"gas_used_p95"`
+	GasUsedAvg   int64  `json:"gas_used_avg"`
+	GasWantedAvg int64  `json:"gas_wanted_avg"`
+	FeeAvg       string `json:"fee_avg"`
+	FeeDenom     string `json:"fee_denom"`
+}
+
+// GetGasStats returns the hourly or daily gas stats per message type, e.g. /gas/stats?period=hour&msg_type=send&from=2023-10-01&to=2023-10-01.
+// period is day by default, and the days are given as GetDailyFees.
+func GetGasStats(a *app.App) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+
+		period := q.Get("period")
+		switch period {
+		case "":
+			period = db.PERIOD_DAY
+		case db.PERIOD_HOUR, db.PERIOD_DAY:
+		default:
+			errors.ErrInvalidParam(rw, http.StatusBadRequest, "period must be hour or day")
+			return
+		}
+
+		from, to, err := parseDays(q.Get("from"), q.Get("to"), time.Now())
+		if err != nil {
+			errors.ErrInvalidParam(rw, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		stats, err := a.DB.GetGasStats(a.ChainInfoIDs(), period, q.Get("msg_type"), from, to)
+		if err != nil {
+			zap.S().Debug("failed to get gas stats ", zap.Error(err))
+			errors.ErrServerUnavailable(rw, http.StatusInternalServerError)
+			return
+		}
+
+		result := make([]GasStat, len(stats))
+		for i, s := range stats {
+			result[i] = GasStat{
+				Period:       s.Period,
+				BucketStart:  s.BucketStart.UTC(),
+				MsgType:      s.MsgType,
+				NumTxs:       s.NumTxs,
+				GasUsedP50:   s.GasUsedP50,
+				GasUsedP95:   s.GasUsedP95,
+				GasUsedAvg:   s.GasUsedAvg,
+				GasWantedAvg: s.GasWantedAvg,
+				FeeAvg:       s.FeeAvg,
+				FeeDenom:     a.Network.BondDenom,
+			}
+		}
+
+		respond(rw, result)
+		return
+	}
+}
+
+// GetGasSummaries returns the gas used per message type of the transactions in the last days, e.g. /gas/summary?days=7.
+func GetGasSummaries(a *app.App) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		days := defaultGasSummaryDays
+		if s := r.URL.Query().Get("days"); s != "" {
+			d, err := strconv.Atoi(s)
+			if err != nil || d <= 0 || d > maxGasSummaryDays {
+				errors.ErrInvalidParam(rw, http.StatusBadRequest, "days must be between 1 and 90")
+				return
+			}
+			days = d
+		}
+
+		since := time.Now().UTC().AddDate(0, 0, -days)
+		summaries, err := a.DB.GetGasSummaries(a.ChainInfoIDs(), since)
+		if err != nil {
+			zap.S().Debug("failed to get gas summaries ", zap.Error(err))
+			errors.ErrServerUnavailable(rw, http.StatusInternalServerError)
+			return
+		}
+
+		result := make([]GasSummary, len(summaries))
+		for i, s := range summaries {
+			result[i] = GasSummary{
+				MsgType:      s.MsgType,
+				NumTxs:       s.NumTxs,
+				GasUsedP50:   s.GasUsedP50,
+				GasUsedP95:   s.GasUsedP95,
+				GasUsedAvg:   s.GasUsedAvg,
+				GasWantedAvg: s.GasWantedAvg,
+				FeeAvg:       s.FeeAvg,
+				FeeDenom:     a.Network.BondDenom,
+			}
+		}
+
+		respond(rw, result)
+		return
+	}
+}
@@ -0,0 +1,90 @@
+package common
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/cosmostation/cosmostation-coreum/app"
+	"github.com/cosmostation/cosmostation-coreum/db"
+	"github.com/cosmostation/cosmostation-coreum/errors"
+	"go.uber.org/zap"
+)
+
+// gasPriceCacheTTL is how long gas prices are served from memory, about a block of the chain.
+const gasPriceCacheTTL = time.Second
+
+// GasPrice is the gas price recommended for a fee denom from the transactions of the last blocks up to Height.
+type GasPrice struct {
+	Denom       string `json:"denom"`
+	Height      int64  `json:"height"`
+	NumTxs      int64  `json:"num_txs"`
+	Slow        string `json:"slow"`
+	Average     string `json:"average"`
+	Fast        string `json:"fast"`
+	MinGasPrice string `json:"min_gas_price"`
+}
+
+// gasPriceCache keeps the gas prices read from the database until they expire.
+type gasPriceCache struct {
+	mu      sync.Mutex
+	prices  []GasPrice
+	expires time.Time
+}
+
+// get returns the cached gas prices, reading them with load when they expired.
+func (c *gasPriceCache) get(now time.Time, load func() ([]GasPrice, error)) ([]GasPrice, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.prices != nil && now.Before(c.expires) {
+		return c.prices, nil
+	}
+
+	prices, err := load()
+	if err != nil {
+		return nil, err
+	}
+	c.prices, c.expires = prices, now.Add(gasPriceCacheTTL)
+	return prices, nil
+}
+
+// GetGasPrices returns the slow, average and fast gas prices per fee denom recommended by the exporter, e.g. /gas/prices.
+func GetGasPrices(a *app.App) http.HandlerFunc {
+	cache := new(gasPriceCache)
+	load := func() ([]GasPrice, error) {
+		rows, err := a.DB.GetGasPrices(a.ChainInfoIDs())
+		if err != nil {
+			return nil, err
+		}
+		return toGasPrices(rows), nil
+	}
+
+	return func(rw http.ResponseWriter, r *http.Request) {
+		prices, err := cache.get(time.Now(), load)
+		if err != nil {
+			zap.S().Debug("failed to get gas prices ", zap.Error(err))
+			errors.ErrServerUnavailable(rw, http.StatusInternalServerError)
+			return
+		}
+
+		respond(rw, prices)
+		return
+	}
+}
+
+func toGasPrices(rows []db.GasPrice) []GasPrice {
+	prices := make([]GasPrice, len(rows))
+	for i, p := range rows {
+		prices[i] = GasPrice{
+			Denom:       p.Denom,
+			Height:      p.Height,
+			NumTxs:      p.NumTxs,
+			Slow:        p.Slow,
+			Average:     p.Average,
+			Fast:        p.Fast,
+			MinGasPrice: p.MinGasPrice,
+		}
+	}
+	return prices
+}
@@ -10,6 +10,13 @@ func RegisterHandlers(a *app.App, r *mux.Router) {
 	r.HandleFunc("/blocks/latest", GetBlocksLatest(a)).Methods("GET")
 	r.HandleFunc("/block_txs/{height}", GetBlockTxs(a)).Methods("GET")
 	r.HandleFunc("/block/{height}", GetBlock(a)).Methods("GET")
+	r.HandleFunc("/txs/search", SearchTransactions(a)).Methods("GET")
 	r.HandleFunc("/txs/{height}", GetTxs(a)).Methods("GET")
 	r.HandleFunc("/block_results/{height}", GetBlockResults(a)).Methods("GET")
+	r.HandleFunc("/block_events/search", SearchBlockEvents(a)).Methods("GET")
+	r.HandleFunc("/fees/daily", GetDailyFees(a)).Methods("GET")
+	r.HandleFunc("/fees/denoms", GetDenomFees(a)).Methods("GET")
+	r.HandleFunc("/gas/stats", GetGasStats(a)).Methods("GET")
+	r.HandleFunc("/gas/summary", GetGasSummaries(a)).Methods("GET")
+	r.HandleFunc("/gas/prices", GetGasPrices(a)).Methods("GET")
 }
@@ -0,0 +1,140 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cosmostation/cosmostation-coreum/app"
+	"github.com/cosmostation/cosmostation-coreum/db"
+	"github.com/cosmostation/cosmostation-coreum/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultSearchLimit is the number of transactions returned when limit is not given.
+	defaultSearchLimit = 20
+	// maxSearchLimit is the maximum number of transactions returned at a time.
+	maxSearchLimit = 100
+	// maxSearchPredicates is the maximum number of event predicates of a search.
+	maxSearchPredicates = 5
+)
+
+// SearchTx is a transaction matching the event predicates of a search.
+type SearchTx struct {
+	ID        int64           `json:"id"`
+	Height    int64           `json:"height"`
+	Hash      string          `json:"hash"`
+	Code      int64           `json:"code"`
+	Timestamp time.Time       `json:"timestamp"`
+	Tx        json.RawMessage `json:"tx"`
+}
+
+// SearchTxs is a page of transactions. Next is the value of before for the next page, 0 on the last page.
+type SearchTxs struct {
+	Txs  []SearchTx `json:"txs"`
+	Next int64      `json:"next"`
+}
+
+// SearchTransactions returns the transactions which emitted events matching every event parameter,
+// e.g. /txs/search?event=transfer.recipient=core1...&event=message.action=/cosmos.bank.v1beta1.MsgSend&limit=20.
+// Pages are requested with before, the id of the last transaction of the previous page.
+func SearchTransactions(a *app.App) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+
+		events := q["event"]
+		if len(events) == 0 {
+			errors.ErrRequiredParam(rw, http.StatusBadRequest, "event is required")
+			return
+		}
+		if len(events) > maxSearchPredicates {
+			errors.ErrOverMaxLimit(rw, http.StatusBadRequest)
+			return
+		}
+		predicates := make([]db.TxEventPredicate, len(events))
+		for i, e := range events {
+			p, err := parseEventPredicate(e)
+			if err != nil {
+				errors.ErrInvalidParam(rw, http.StatusBadRequest, err.Error())
+				return
+			}
+			predicates[i] = p
+		}
+
+		before, limit, err := parsePage(q.Get("before"), q.Get("limit"))
+		if err != nil {
+			errors.ErrInvalidParam(rw, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		txs, err := a.DB.SearchTransactions(a.ChainInfoIDs(), predicates, before, limit)
+		if err != nil {
+			zap.S().Debug("failed to search transactions ", zap.Error(err))
+			errors.ErrServerUnavailable(rw, http.StatusInternalServerError)
+			return
+		}
+
+		result := SearchTxs{Txs: make([]SearchTx, len(txs))}
+		for i, t := range txs {
+			result.Txs[i] = SearchTx{
+				ID:        t.ID,
+				Height:    t.Height,
+				Hash:      t.Hash,
+				Code:      int64(t.Code),
+				Timestamp: t.Timestamp,
+				Tx:        json.RawMessage(t.Chunk),
+			}
+		}
+		if len(txs) == limit {
+			result.Next = txs[len(txs)-1].ID
+		}
+
+		respond(rw, result)
+		return
+	}
+}
+
+// parseEventPredicate parses type.key=value into a predicate. The value and then the key may be omitted,
+// as in type.key or type. Only the first dot and equal sign separate, so keys and values may contain them.
+func parseEventPredicate(s string) (db.TxEventPredicate, error) {
+	var p db.TxEventPredicate
+
+	left, value, hasValue := strings.Cut(s, "=")
+	p.Type, p.Key, _ = strings.Cut(left, ".")
+	p.Value = value
+
+	if p.Type == "" {
+		return p, fmt.Errorf("event type is empty in %q", s)
+	}
+	if hasValue && p.Key == "" {
+		return p, fmt.Errorf("event key is empty in %q", s)
+	}
+	return p, nil
+}
+
+// parsePage parses the id of the last transaction of the previous page and the number of transactions of the page.
+func parsePage(beforeStr, limitStr string) (before int64, limit int, err error) {
+	if beforeStr != "" {
+		before, err = strconv.ParseInt(beforeStr, 10, 64)
+		if err != nil || before < 0 {
+			return 0, 0, fmt.Errorf("invalid before %q", beforeStr)
+		}
+	}
+
+	limit = defaultSearchLimit
+	if limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			return 0, 0, fmt.Errorf("invalid limit %q", limitStr)
+		}
+		if limit > maxSearchLimit {
+			limit = maxSearchLimit
+		}
+	}
+
+	return before, limit, nil
+}
@@ -0,0 +1,47 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/cosmostation/cosmostation-coreum/db"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEventPredicate(t *testing.T) {
+	cases := []struct {
+		in       string
+		expected db.TxEventPredicate
+	}{
+		{"transfer.recipient=core1abc", db.TxEventPredicate{Type: "transfer", Key: "recipient", Value: "core1abc"}},
+		{"message.action=/cosmos.bank.v1beta1.MsgSend", db.TxEventPredicate{Type: "message", Key: "action", Value: "/cosmos.bank.v1beta1.MsgSend"}},
+		{"transfer.recipient", db.TxEventPredicate{Type: "transfer", Key: "recipient"}},
+		{"transfer", db.TxEventPredicate{Type: "transfer"}},
+	}
+	for _, c := range cases {
+		p, err := parseEventPredicate(c.in)
+		require.NoError(t, err, c.in)
+		require.Equal(t, c.expected, p, c.in)
+	}
+
+	for _, in := range []string{"", ".recipient=a", "transfer=a"} {
+		_, err := parseEventPredicate(in)
+		require.Error(t, err, in)
+	}
+}
+
+func TestParsePage(t *testing.T) {
+	before, limit, err := parsePage("", "")
+	require.NoError(t, err)
+	require.Equal(t, int64(0), before)
+	require.Equal(t, defaultSearchLimit, limit)
+
+	before, limit, err = parsePage("1000", "500")
+	require.NoError(t, err)
+	require.Equal(t, int64(1000), before)
+	require.Equal(t, maxSearchLimit, limit)
+
+	_, _, err = parsePage("x", "")
+	require.Error(t, err)
+	_, _, err = parsePage("", "0")
+	require.Error(t, err)
+}
@@ -30,7 +30,7 @@ func GetTxs(a *app.App) http.HandlerFunc {
 
 		t := Txs{}
 		// get transactions
-		_, txs, err := a.Client.RPC.GetBlockAndTxsFromNode(custom.AppCodec, height)
+		_, txs, err := a.Client.GetBlockAndTxsFromNode(custom.EncodingConfigAt(a.Config.Chain.ChainID, height), height)
 		if err != nil {
 			zap.S().Debug("failed to parse HTTP args ", zap.Error(err))
 			errors.ErrServerUnavailable(rw, http.StatusInternalServerError)
@@ -38,7 +38,7 @@ func GetTxs(a *app.App) http.HandlerFunc {
 		}
 
 		for i := range txs {
-			raw, err := custom.AppCodec.MarshalJSON(txs[i])
+			raw, err := custom.CodecAt(a.Config.Chain.ChainID, height).MarshalJSON(txs[i])
 			if err != nil {
 				zap.S().Debugf("failed to marshal tx hash = %s, err = %s\n", txs[i].TxHash, zap.Error(err))
 				errors.ErrServerUnavailable(rw, http.StatusInternalServerError)
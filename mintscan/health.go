@@ -0,0 +1,19 @@
+package mintscan
+
+import (
+	"github.com/cosmostation/cosmostation-coreum/app"
+	"github.com/cosmostation/cosmostation-coreum/health"
+)
+
+// ReadinessChecks returns checks which fail when the node or the database serving the API is not reachable.
+func ReadinessChecks(a *app.App) health.Checks {
+	return health.Checks{
+		"node": health.Bound(func() error {
+			_, err := a.Client.GetStatus()
+			return err
+		}),
+		"db": health.Bound(func() error {
+			return a.DB.Ping()
+		}),
+	}
+}
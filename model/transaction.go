@@ -1,10 +1,11 @@
 package model
 
 import (
-	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/cosmostation/cosmostation-coreum/app"
+	"github.com/cosmostation/cosmostation-coreum/db"
 	mdschema "github.com/cosmostation/mintscan-database/schema"
 )
 
@@ -50,15 +51,17 @@ type TxList struct {
 // }
 
 // ParseTransaction receives single transaction from database and return it after unmarshal them.
-func ParseTransaction(a *app.App, tx mdschema.Transaction) (result *ResultTx) {
-	var jsonRaws json.RawMessage
-
-	jsonRaws = tx.Chunk
+// It returns an error when the chunk of the transaction can not be decompressed.
+func ParseTransaction(a *app.App, tx mdschema.Transaction) (result *ResultTx, err error) {
+	jsonRaws, err := db.DecompressChunk(tx.Chunk)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse transaction %s: %s", tx.Hash, err)
+	}
 
 	if tx.ID != 0 {
 		header := ResultTxHeader{
 			ID:        tx.ID,
-			ChainID:   a.ChainNumMap[tx.ChainInfoID],
+			ChainID:   a.ChainIDAt(tx.Height, tx.ChainInfoID),
 			BlockID:   tx.BlockID,
 			Timestamp: tx.Timestamp.Format(time.RFC3339),
 		}
@@ -69,13 +72,19 @@ func ParseTransaction(a *app.App, tx mdschema.Transaction) (result *ResultTx) {
 		}
 	}
 
-	return result
+	return result, nil
 }
 
 // ParseTransactions receives result transactions from database and return them after unmarshal them.
-func ParseTransactions(a *app.App, txs []mdschema.Transaction) (results []*ResultTx) {
+// It fails on the first transaction which can not be parsed, so that handlers respond an error
+// instead of a list with missing transactions.
+func ParseTransactions(a *app.App, txs []mdschema.Transaction) (results []*ResultTx, err error) {
 	for i := range txs {
-		results = append(results, ParseTransaction(a, txs[i]))
+		result, err := ParseTransaction(a, txs[i])
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
 	}
-	return results
+	return results, nil
 }
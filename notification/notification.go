@@ -1,6 +1,7 @@
 package notification
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/cosmostation/cosmostation-coreum/db"
@@ -36,7 +37,8 @@ func NewNotification() *Notification {
 // Push sends push notification to local notification server and it delivers the message to
 // its respective device. Uses a push notification micro server called gorush.
 // More information can be found here in this link. https://github.com/appleboy/gorush
-func (nof *Notification) Push(np types.NotificationPayload, tokens []string, target string) {
+// An error is returned when the server does not accept the notification, so that it can be retried.
+func (nof *Notification) Push(np types.NotificationPayload, tokens []string, target string) error {
 	var notifications []types.Notification
 
 	// Create new notification payload for a user sending tokens
@@ -71,8 +73,16 @@ func (nof *Notification) Push(np types.NotificationPayload, tokens []string, tar
 		}
 
 		// Send push notification
-		nof.client.R().SetBody(nsp)
+		resp, err := nof.client.R().SetBody(nsp).Post("/api/push")
+		if err != nil {
+			return fmt.Errorf("failed to send push notification: %s", err)
+		}
+		if resp.IsError() {
+			return fmt.Errorf("failed to send push notification: %s", resp.Status())
+		}
 	}
+
+	return nil
 }
 
 // VerifyAccountStatus verifes account status before sending notification to its local server.
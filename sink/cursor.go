@@ -0,0 +1,70 @@
+package sink
+
+// cursorWindow is the number of last heights whose block hashes are kept per chain,
+// which bounds how deep a fork can be verified and rolled back by sinks other than Postgres.
+const cursorWindow = 1000
+
+// cursorKey identifies the data of a chain written with a kind.
+type cursorKey struct {
+	kind    string
+	chainID string
+}
+
+// cursor is the latest height written and the hashes of the blocks of the last heights.
+type cursor struct {
+	latest int64
+	hashes map[int64]string
+}
+
+// cursors keeps the progress of every chain and kind written to a sink which can not be queried like a database.
+type cursors map[cursorKey]*cursor
+
+// add records the block of the chain written with the kind at the height.
+func (c cursors) add(kind, chainID string, height int64, hash string) {
+	k := cursorKey{kind, chainID}
+	cur, ok := c[k]
+	if !ok {
+		cur = &cursor{hashes: make(map[int64]string)}
+		c[k] = cur
+	}
+
+	if height > cur.latest {
+		cur.latest = height
+	}
+	cur.hashes[height] = hash
+	delete(cur.hashes, height-cursorWindow)
+}
+
+// latest returns the highest height of the chain written with the kind, 0 when nothing is written.
+func (c cursors) latest(kind, chainID string) int64 {
+	if cur, ok := c[cursorKey{kind, chainID}]; ok {
+		return cur.latest
+	}
+	return 0
+}
+
+// hash returns the hash of the block of the chain written with the kind at the height,
+// empty when it is not written or out of the window.
+func (c cursors) hash(kind, chainID string, height int64) string {
+	if cur, ok := c[cursorKey{kind, chainID}]; ok {
+		return cur.hashes[height]
+	}
+	return ""
+}
+
+// rollback forgets the blocks of the chain written at the height and above with any kind.
+func (c cursors) rollback(chainID string, height int64) {
+	for k, cur := range c {
+		if k.chainID != chainID {
+			continue
+		}
+		for h := range cur.hashes {
+			if h >= height {
+				delete(cur.hashes, h)
+			}
+		}
+		if cur.latest >= height {
+			cur.latest = height - 1
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package sink
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/cosmostation/cosmostation-coreum/db"
+
+	mdschema "github.com/cosmostation/mintscan-database/schema"
+)
+
+// Record kinds written by JSONL sink. KindBasic and KindRaw also name the heights reported by Sink.LatestHeight.
+const (
+	KindBasic          = "basic"
+	KindRaw            = "raw"
+	KindRefine         = "refine"
+	KindRefineRealTime = "refine_realtime"
+	KindGasPrices      = "gas_prices"
+	// KindRollback records that the data of the chain at Height and above is rolled back.
+	KindRollback = "rollback"
+)
+
+// Record is a line written by JSONL sink. ChainID, Height and Hash identify the block of basic and raw records,
+// so that the heights written by a previous run are recovered when the file is opened again.
+type Record struct {
+	Kind    string          `json:"kind"`
+	ChainID string          `json:"chain_id,omitempty"`
+	Height  int64           `json:"height,omitempty"`
+	Hash    string          `json:"hash,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// JSONL writes every record as a line of JSON.
+type JSONL struct {
+	mu      sync.Mutex
+	w       *bufio.Writer
+	c       io.Closer
+	cursors cursors
+}
+
+// NewJSONL returns a sink writing to w. w is closed by Close.
+func NewJSONL(w io.WriteCloser) *JSONL {
+	return &JSONL{w: bufio.NewWriter(w), c: w, cursors: make(cursors)}
+}
+
+// WriteBasicData implements Sink.
+func (j *JSONL) WriteBasicData(basic *db.ExportedData) error {
+	r := Record{Kind: KindBasic, ChainID: basic.ChainID}
+	if basic.Block != nil {
+		r.Height, r.Hash = basic.Block.Height, basic.Block.Hash
+	}
+	return j.write(r, basic)
+}
+
+// WriteRawData implements Sink.
+func (j *JSONL) WriteRawData(raw *mdschema.RawData) error {
+	r := Record{Kind: KindRaw}
+	if raw.Block != nil {
+		r.ChainID, r.Height, r.Hash = raw.Block.ChainID, raw.Block.Height, raw.Block.BlockHash
+	}
+	return j.write(r, raw)
+}
+
+// WriteRefineData implements Sink.
+func (j *JSONL) WriteRefineData(refine *mdschema.RefineData) error {
+	return j.write(Record{Kind: KindRefine}, refine)
+}
+
+// WriteRefineRealTimeData implements Sink.
+func (j *JSONL) WriteRefineRealTimeData(basic *db.ExportedData) error {
+	r := Record{Kind: KindRefineRealTime, ChainID: basic.ChainID}
+	if basic.Block != nil {
+		r.Height, r.Hash = basic.Block.Height, basic.Block.Hash
+	}
+	return j.write(r, basic)
+}
+
+// WriteGasPrices implements Sink.
+func (j *JSONL) WriteGasPrices(chainInfoID int, prices []db.GasPrice) error {
+	return j.write(Record{Kind: KindGasPrices}, prices)
+}
+
+// LatestHeight implements Sink.
+func (j *JSONL) LatestHeight(kind, chainID string) (int64, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.cursors.latest(kind, chainID), nil
+}
+
+// BlockHash implements Sink. Only the hashes of the last heights written are known.
+func (j *JSONL) BlockHash(kind, chainID string, height int64) (string, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.cursors.hash(kind, chainID, height), nil
+}
+
+// Rollback implements Sink. Records can not be removed from the file, so a rollback record is appended
+// and readers are expected to drop the records of the chain at its height and above.
+func (j *JSONL) Rollback(chainID string, height int64) error {
+	return j.write(Record{Kind: KindRollback, ChainID: chainID, Height: height}, nil)
+}
+
+// Close implements Sink.
+func (j *JSONL) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if err := j.w.Flush(); err != nil {
+		return err
+	}
+	return j.c.Close()
+}
+
+// write appends a record with the data and flushes it, so that a line is never left half written.
+func (j *JSONL) write(r Record, data interface{}) error {
+	if data != nil {
+		bz, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		r.Data = bz
+	}
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if _, err := j.w.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	if err := j.w.Flush(); err != nil {
+		return err
+	}
+	j.track(r)
+	return nil
+}
+
+// recover reads the records written before and recovers the heights of every chain.
+// Lines which are not valid records, such as a line left half written by a crash, are skipped,
+// and a last line without a line break is terminated so that the next record starts on its own line.
+func (j *JSONL) recover(r io.Reader) error {
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+
+		var rec Record
+		if json.Unmarshal(line, &rec) == nil {
+			rec.Data = nil
+			j.track(rec)
+		}
+
+		if err == io.EOF {
+			if len(line) > 0 {
+				if _, err := j.w.Write([]byte{'\n'}); err != nil {
+					return err
+				}
+				return j.w.Flush()
+			}
+			return nil
+		}
+	}
+}
+
+// track moves the cursors by a record written.
+func (j *JSONL) track(r Record) {
+	switch r.Kind {
+	case KindBasic, KindRaw:
+		if r.ChainID != "" {
+			j.cursors.add(r.Kind, r.ChainID, r.Height, r.Hash)
+		}
+	case KindRefineRealTime:
+		if r.ChainID != "" {
+			j.cursors.add(KindBasic, r.ChainID, r.Height, r.Hash)
+		}
+	case KindRollback:
+		j.cursors.rollback(r.ChainID, r.Height)
+	}
+}
+
+// nopCloser keeps the standard output open when the sink is closed.
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
@@ -0,0 +1,72 @@
+package sink
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/cosmostation/cosmostation-coreum/db"
+	"github.com/stretchr/testify/require"
+
+	mdschema "github.com/cosmostation/mintscan-database/schema"
+)
+
+func TestJSONLWritesOneRecordPerLine(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewJSONL(nopCloser{&buf})
+
+	require.NoError(t, s.WriteBasicData(&db.ExportedData{BasicData: &mdschema.BasicData{}}))
+	require.NoError(t, s.WriteRawData(&mdschema.RawData{}))
+	require.NoError(t, s.WriteRefineData(&mdschema.RefineData{}))
+	require.NoError(t, s.Close())
+
+	var kinds []string
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var r Record
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &r))
+		kinds = append(kinds, r.Kind)
+	}
+	require.Equal(t, []string{KindBasic, KindRaw, KindRefine}, kinds)
+}
+
+func TestJSONLRecoversHeights(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewJSONL(nopCloser{&buf})
+
+	for h := int64(1); h <= 3; h++ {
+		basic := &db.ExportedData{BasicData: &mdschema.BasicData{Block: &mdschema.Block{Height: h, Hash: fmt.Sprint("B", h)}}, ChainID: "test-1"}
+		require.NoError(t, s.WriteBasicData(basic))
+		require.NoError(t, s.WriteRawData(&mdschema.RawData{Block: &mdschema.RawBlock{ChainID: "test-1", Height: h, BlockHash: fmt.Sprint("B", h)}}))
+	}
+	require.NoError(t, s.Rollback("test-1", 3))
+	require.NoError(t, s.Close())
+
+	// a line left half written by a crash is skipped
+	buf.WriteString(`{"kind":"basic","chain_id":"test-1","height":4`)
+
+	var out bytes.Buffer
+	recovered := NewJSONL(nopCloser{&out})
+	require.NoError(t, recovered.recover(&buf))
+	require.Equal(t, "\n", out.String())
+
+	for _, kind := range []string{KindBasic, KindRaw} {
+		height, err := recovered.LatestHeight(kind, "test-1")
+		require.NoError(t, err)
+		require.Equal(t, int64(2), height)
+
+		hash, err := recovered.BlockHash(kind, "test-1", 2)
+		require.NoError(t, err)
+		require.Equal(t, "B2", hash)
+
+		hash, err = recovered.BlockHash(kind, "test-1", 3)
+		require.NoError(t, err)
+		require.Empty(t, hash)
+	}
+
+	height, err := recovered.LatestHeight(KindBasic, "test-2")
+	require.NoError(t, err)
+	require.Equal(t, int64(0), height)
+}
@@ -0,0 +1,161 @@
+package sink
+
+import (
+	"sync"
+
+	"github.com/cosmostation/cosmostation-coreum/db"
+
+	mdschema "github.com/cosmostation/mintscan-database/schema"
+)
+
+// Memory keeps every record in memory. It is meant for tests which run the exporter without a database.
+type Memory struct {
+	mu             sync.Mutex
+	basic          []*db.ExportedData
+	raw            []*mdschema.RawData
+	refine         []*mdschema.RefineData
+	refineRealTime []*db.ExportedData
+	gasPrices      map[int][]db.GasPrice
+	cursors        cursors
+}
+
+// NewMemory returns an empty in-memory sink.
+func NewMemory() *Memory {
+	return &Memory{gasPrices: make(map[int][]db.GasPrice), cursors: make(cursors)}
+}
+
+// WriteBasicData implements Sink.
+func (m *Memory) WriteBasicData(basic *db.ExportedData) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.basic = append(m.basic, basic)
+	if basic.Block != nil {
+		m.cursors.add(KindBasic, basic.ChainID, basic.Block.Height, basic.Block.Hash)
+	}
+	return nil
+}
+
+// WriteRawData implements Sink.
+func (m *Memory) WriteRawData(raw *mdschema.RawData) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.raw = append(m.raw, raw)
+	if raw.Block != nil {
+		m.cursors.add(KindRaw, raw.Block.ChainID, raw.Block.Height, raw.Block.BlockHash)
+	}
+	return nil
+}
+
+// WriteRefineData implements Sink.
+func (m *Memory) WriteRefineData(refine *mdschema.RefineData) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.refine = append(m.refine, refine)
+	return nil
+}
+
+// WriteRefineRealTimeData implements Sink.
+func (m *Memory) WriteRefineRealTimeData(basic *db.ExportedData) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.refineRealTime = append(m.refineRealTime, basic)
+	if basic.Block != nil {
+		m.cursors.add(KindBasic, basic.ChainID, basic.Block.Height, basic.Block.Hash)
+	}
+	return nil
+}
+
+// WriteGasPrices implements Sink.
+func (m *Memory) WriteGasPrices(chainInfoID int, prices []db.GasPrice) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.gasPrices[chainInfoID] = prices
+	return nil
+}
+
+// LatestHeight implements Sink.
+func (m *Memory) LatestHeight(kind, chainID string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.cursors.latest(kind, chainID), nil
+}
+
+// BlockHash implements Sink.
+func (m *Memory) BlockHash(kind, chainID string, height int64) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.cursors.hash(kind, chainID, height), nil
+}
+
+// Rollback implements Sink.
+func (m *Memory) Rollback(chainID string, height int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	basic := m.basic[:0]
+	for _, b := range m.basic {
+		if b.ChainID != chainID || b.Block == nil || b.Block.Height < height {
+			basic = append(basic, b)
+		}
+	}
+	m.basic = basic
+
+	refineRealTime := m.refineRealTime[:0]
+	for _, b := range m.refineRealTime {
+		if b.ChainID != chainID || b.Block == nil || b.Block.Height < height {
+			refineRealTime = append(refineRealTime, b)
+		}
+	}
+	m.refineRealTime = refineRealTime
+
+	raw := m.raw[:0]
+	for _, r := range m.raw {
+		if r.Block == nil || r.Block.ChainID != chainID || r.Block.Height < height {
+			raw = append(raw, r)
+		}
+	}
+	m.raw = raw
+
+	m.cursors.rollback(chainID, height)
+	return nil
+}
+
+// Close implements Sink.
+func (m *Memory) Close() error {
+	return nil
+}
+
+// BasicData returns the basic data written so far in order.
+func (m *Memory) BasicData() []*db.ExportedData {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*db.ExportedData(nil), m.basic...)
+}
+
+// RawData returns the raw data written so far in order.
+func (m *Memory) RawData() []*mdschema.RawData {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*mdschema.RawData(nil), m.raw...)
+}
+
+// RefineData returns the refined data written so far in order.
+func (m *Memory) RefineData() []*mdschema.RefineData {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*mdschema.RefineData(nil), m.refine...)
+}
+
+// RefineRealTimeData returns the data refined while following the chain so far in order.
+func (m *Memory) RefineRealTimeData() []*db.ExportedData {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*db.ExportedData(nil), m.refineRealTime...)
+}
+
+// GasPrices returns the gas prices of the chain written last.
+func (m *Memory) GasPrices(chainInfoID int) []db.GasPrice {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]db.GasPrice(nil), m.gasPrices[chainInfoID]...)
+}
@@ -0,0 +1,43 @@
+package sink
+
+import (
+	"testing"
+
+	"github.com/cosmostation/cosmostation-coreum/db"
+	"github.com/stretchr/testify/require"
+
+	mdschema "github.com/cosmostation/mintscan-database/schema"
+)
+
+func TestMemoryKeepsOrder(t *testing.T) {
+	var s Sink = NewMemory()
+
+	first, second := &mdschema.RawData{}, &mdschema.RawData{}
+	require.NoError(t, s.WriteRawData(first))
+	require.NoError(t, s.WriteRawData(second))
+
+	raw := s.(*Memory).RawData()
+	require.Len(t, raw, 2)
+	require.Same(t, first, raw[0])
+	require.Same(t, second, raw[1])
+}
+
+func TestMemoryRollback(t *testing.T) {
+	s := NewMemory()
+
+	for h := int64(1); h <= 3; h++ {
+		for _, chainID := range []string{"test-1", "test-2"} {
+			basic := &db.ExportedData{BasicData: &mdschema.BasicData{Block: &mdschema.Block{Height: h, Hash: "B"}}, ChainID: chainID}
+			require.NoError(t, s.WriteBasicData(basic))
+		}
+	}
+	require.NoError(t, s.Rollback("test-1", 2))
+
+	require.Len(t, s.BasicData(), 4)
+	height, err := s.LatestHeight(KindBasic, "test-1")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), height)
+	height, err = s.LatestHeight(KindBasic, "test-2")
+	require.NoError(t, err)
+	require.Equal(t, int64(3), height)
+}
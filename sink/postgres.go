@@ -0,0 +1,117 @@
+package sink
+
+import (
+	"fmt"
+
+	"github.com/cosmostation/cosmostation-coreum/db"
+
+	mdschema "github.com/cosmostation/mintscan-database/schema"
+)
+
+// Postgres writes basic and refined data to the database and raw data to the raw database.
+type Postgres struct {
+	DB    *db.Database
+	RawDB *db.RawDatabase
+}
+
+// NewPostgres returns a sink writing to the given databases.
+func NewPostgres(d *db.Database, rawDB *db.RawDatabase) *Postgres {
+	return &Postgres{DB: d, RawDB: rawDB}
+}
+
+// WriteBasicData implements Sink.
+func (p *Postgres) WriteBasicData(basic *db.ExportedData) error {
+	return p.DB.InsertExportedData(basic)
+}
+
+// WriteRawData implements Sink.
+func (p *Postgres) WriteRawData(raw *mdschema.RawData) error {
+	return p.RawDB.InsertRawData(raw)
+}
+
+// WriteRefineData implements Sink.
+func (p *Postgres) WriteRefineData(refine *mdschema.RefineData) error {
+	return p.DB.InsertRefineData(refine)
+}
+
+// WriteRefineRealTimeData implements Sink.
+func (p *Postgres) WriteRefineRealTimeData(basic *db.ExportedData) error {
+	return p.DB.InsertRefineRealTimeData(basic.BasicData)
+}
+
+// WriteGasPrices implements Sink.
+func (p *Postgres) WriteGasPrices(chainInfoID int, prices []db.GasPrice) error {
+	return p.DB.ReplaceGasPrices(chainInfoID, prices)
+}
+
+// LatestHeight implements Sink.
+// A rollback of raw data left pending by a failed Rollback is finished before the raw height is reported.
+func (p *Postgres) LatestHeight(kind, chainID string) (int64, error) {
+	chainInfoID, err := p.DB.GetChainInfoID(chainID)
+	if err != nil {
+		return 0, err
+	}
+
+	if kind == KindRaw {
+		if err := p.rollbackRaw(chainInfoID, chainID); err != nil {
+			return 0, err
+		}
+		return p.RawDB.GetChainLatestBlockHeight(chainID)
+	}
+
+	if chainInfoID == 0 {
+		return 0, nil
+	}
+	return p.DB.GetLatestBlockHeight(chainInfoID)
+}
+
+// BlockHash implements Sink.
+func (p *Postgres) BlockHash(kind, chainID string, height int64) (string, error) {
+	if kind == KindRaw {
+		return p.RawDB.GetBlockHash(chainID, height)
+	}
+
+	chainInfoID, err := p.DB.GetChainInfoID(chainID)
+	if err != nil || chainInfoID == 0 {
+		return "", err
+	}
+	return p.DB.GetBlockHash(chainInfoID, height)
+}
+
+// Rollback implements Sink. The basic data and its events are deleted in one transaction which also marks
+// the raw data to be rolled back, so a failed delete of raw data is retried by the next Rollback or LatestHeight.
+func (p *Postgres) Rollback(chainID string, height int64) error {
+	chainInfoID, err := p.DB.GetChainInfoID(chainID)
+	if err != nil {
+		return err
+	}
+
+	if err := p.DB.DeleteBlocksFrom(chainInfoID, chainID, height); err != nil {
+		return fmt.Errorf("failed to roll back database : %s", err)
+	}
+	return p.rollbackRaw(chainInfoID, chainID)
+}
+
+// rollbackRaw deletes the raw data from the height of the pending raw rollback of the chain and clears it.
+func (p *Postgres) rollbackRaw(chainInfoID int, chainID string) error {
+	height, err := p.DB.GetIndexCursor(chainInfoID, db.RawRollbackCursor)
+	if err != nil {
+		return fmt.Errorf("failed to get pending raw rollback : %s", err)
+	}
+	if height == 0 {
+		return nil
+	}
+
+	if err := p.RawDB.DeleteBlocksFrom(chainID, height); err != nil {
+		return fmt.Errorf("failed to roll back raw database : %s", err)
+	}
+	if err := p.DB.SetIndexCursor(chainInfoID, db.RawRollbackCursor, 0); err != nil {
+		return fmt.Errorf("failed to clear pending raw rollback : %s", err)
+	}
+	return nil
+}
+
+// Close implements Sink. The connection pools are owned by the app, so they are left open.
+func (p *Postgres) Close() error {
+	return nil
+}
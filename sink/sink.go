@@ -0,0 +1,74 @@
+// Package sink defines where the exporter writes the data it extracts from the chain.
+package sink
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cosmostation/cosmostation-coreum/db"
+
+	mdschema "github.com/cosmostation/mintscan-database/schema"
+)
+
+const (
+	// POSTGRES_SINK writes to the databases of mintscan-database. It is the default sink.
+	POSTGRES_SINK = "postgres"
+	// JSONL_SINK appends every record as a line of JSON to a file.
+	JSONL_SINK = "jsonl"
+	// STDOUT_SINK writes every record as a line of JSON to the standard output.
+	STDOUT_SINK = "stdout"
+)
+
+// Sink receives the data extracted for a height and stores it, and reports how far each chain is stored,
+// so that sync resumes from the sink it writes to.
+// Each write is expected to be atomic: either all of the data is stored or none of it.
+type Sink interface {
+	// WriteBasicData stores the data extracted by basic mode.
+	WriteBasicData(basic *db.ExportedData) error
+	// WriteRawData stores the data extracted by raw mode.
+	WriteRawData(raw *mdschema.RawData) error
+	// WriteGasPrices replaces the gas prices recommended for the chain.
+	WriteGasPrices(chainInfoID int, prices []db.GasPrice) error
+	// LatestHeight returns the highest height of the chain stored with the kind, KindBasic or KindRaw.
+	// 0 is returned when nothing is stored.
+	LatestHeight(kind, chainID string) (int64, error)
+	// BlockHash returns the hash of the block of the chain stored with the kind at the height.
+	// An empty string is returned when the block is not stored.
+	BlockHash(kind, chainID string, height int64) (string, error)
+	// Rollback removes the basic and raw data of the chain stored at the height and above.
+	Rollback(chainID string, height int64) error
+	// WriteRefineData stores blocks and transactions refined from raw data.
+	WriteRefineData(refine *mdschema.RefineData) error
+	// WriteRefineRealTimeData stores the block and transactions refined while following the chain.
+	// They are stored as basic data, so LatestHeight of KindBasic includes them.
+	WriteRefineRealTimeData(basic *db.ExportedData) error
+	// Close flushes buffered data and releases the resources held by the sink.
+	Close() error
+}
+
+// Open returns the sink of the given kind. path is the file written by JSONL sink.
+func Open(kind, path string, d *db.Database, rawDB *db.RawDatabase) (Sink, error) {
+	switch kind {
+	case POSTGRES_SINK, "":
+		return NewPostgres(d, rawDB), nil
+	case JSONL_SINK:
+		if path == "" {
+			return nil, fmt.Errorf("path of %s sink is not set", JSONL_SINK)
+		}
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_RDWR, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s : %s", path, err)
+		}
+		// the records written by a previous run tell where sync resumes
+		j := NewJSONL(f)
+		if err := j.recover(f); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("failed to read %s : %s", path, err)
+		}
+		return j, nil
+	case STDOUT_SINK:
+		return NewJSONL(nopCloser{os.Stdout}), nil
+	default:
+		return nil, fmt.Errorf("unknown sink %s", kind)
+	}
+}
@@ -0,0 +1,175 @@
+// Package subscription follows new blocks through the websocket of a CometBFT node.
+package subscription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	rpchttp "github.com/cometbft/cometbft/rpc/client/http"
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+	tmtypes "github.com/cometbft/cometbft/types"
+)
+
+const (
+	subscriber    = "chain-exporter"
+	queryNewBlock = "tm.event='NewBlock'"
+	queryTx       = "tm.event='Tx'"
+)
+
+var (
+	// ErrIdle is returned by Err when no event was received for the idle timeout.
+	ErrIdle = errors.New("no event received within the idle timeout")
+
+	// ErrClosed is returned by Err when the rpc client closed the events, e.g. when the websocket was disconnected.
+	ErrClosed = errors.New("events closed by the rpc client")
+)
+
+// Subscription delivers the heights of blocks reported by NewBlock and Tx events.
+// Heights are delivered in increasing order. A slow receiver only gets the latest height,
+// which is enough to sync every height up to it.
+type Subscription struct {
+	client  *rpchttp.HTTP
+	heights chan int64
+	quit    chan struct{}
+	once    sync.Once
+
+	mu  sync.Mutex
+	err error
+}
+
+// Subscribe connects to the websocket of the node at remote (e.g. tcp://localhost:26657) and subscribes to NewBlock and Tx events.
+// The subscription ends when ctx is canceled, Close is called, the rpc client closes the events or no event is received for idleTimeout.
+func Subscribe(ctx context.Context, remote string, idleTimeout time.Duration) (*Subscription, error) {
+	c, err := rpchttp.New(remote, "/websocket")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rpc client: %s", err)
+	}
+	if err := c.Start(); err != nil {
+		return nil, fmt.Errorf("failed to connect websocket: %s", err)
+	}
+
+	blocks, err := c.Subscribe(ctx, subscriber, queryNewBlock, 16)
+	if err != nil {
+		c.Stop()
+		return nil, fmt.Errorf("failed to subscribe %s: %s", queryNewBlock, err)
+	}
+	txs, err := c.Subscribe(ctx, subscriber, queryTx, 256)
+	if err != nil {
+		c.Stop()
+		return nil, fmt.Errorf("failed to subscribe %s: %s", queryTx, err)
+	}
+
+	s := &Subscription{
+		client:  c,
+		heights: make(chan int64),
+		quit:    make(chan struct{}),
+	}
+	go s.run(ctx, blocks, txs, idleTimeout)
+
+	return s, nil
+}
+
+// Heights returns the channel of block heights. It is closed when the subscription ends.
+func (s *Subscription) Heights() <-chan int64 {
+	return s.heights
+}
+
+// Err returns why the subscription ended. It returns nil while the subscription is running or after Close.
+func (s *Subscription) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Close ends the subscription and closes the websocket.
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		close(s.quit)
+		s.client.Stop()
+	})
+}
+
+func (s *Subscription) run(ctx context.Context, blocks, txs <-chan ctypes.ResultEvent, idleTimeout time.Duration) {
+	defer close(s.heights)
+
+	idle := time.NewTimer(idleTimeout)
+	defer idle.Stop()
+
+	var latest, sent int64
+	for {
+		// only send when there is a height newer than the one sent last
+		var out chan int64
+		if latest > sent {
+			out = s.heights
+		}
+
+		select {
+		case e, ok := <-blocks:
+			if !ok {
+				s.closed()
+				return
+			}
+			latest = maxHeight(latest, eventHeight(e))
+		case e, ok := <-txs:
+			if !ok {
+				s.closed()
+				return
+			}
+			latest = maxHeight(latest, eventHeight(e))
+		case out <- latest:
+			sent = latest
+			continue
+		case <-idle.C:
+			s.setErr(ErrIdle)
+			return
+		case <-ctx.Done():
+			s.setErr(ctx.Err())
+			return
+		case <-s.quit:
+			return
+		}
+
+		if !idle.Stop() {
+			<-idle.C
+		}
+		idle.Reset(idleTimeout)
+	}
+}
+
+// closed ends the subscription with ErrClosed, unless the events were closed by Close.
+func (s *Subscription) closed() {
+	select {
+	case <-s.quit:
+	default:
+		s.setErr(ErrClosed)
+	}
+}
+
+func (s *Subscription) setErr(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+// eventHeight returns the height of a NewBlock or Tx event, 0 for other events.
+func eventHeight(e ctypes.ResultEvent) int64 {
+	switch data := e.Data.(type) {
+	case tmtypes.EventDataNewBlock:
+		if data.Block != nil {
+			return data.Block.Height
+		}
+	case tmtypes.EventDataTx:
+		return data.Height
+	}
+	return 0
+}
+
+func maxHeight(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
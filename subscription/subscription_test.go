@@ -0,0 +1,157 @@
+package subscription
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+
+	ctypes "github.com/cometbft/cometbft/rpc/core/types"
+)
+
+// fakeNode is a local stand-in of the websocket endpoint of a CometBFT node.
+type fakeNode struct {
+	srv *httptest.Server
+
+	mu    sync.Mutex
+	conn  *websocket.Conn
+	subs  map[string]interface{} // query -> id of the subscribe request
+	ready chan struct{}
+}
+
+func newFakeNode(t *testing.T) *fakeNode {
+	n := &fakeNode{subs: make(map[string]interface{}), ready: make(chan struct{})}
+	upgrader := websocket.Upgrader{}
+
+	n.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		n.mu.Lock()
+		n.conn = conn
+		n.mu.Unlock()
+
+		for {
+			var req struct {
+				ID     interface{}       `json:"id"`
+				Method string            `json:"method"`
+				Params map[string]string `json:"params"`
+			}
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			if req.Method != "subscribe" {
+				continue
+			}
+
+			n.mu.Lock()
+			n.subs[req.Params["query"]] = req.ID
+			conn.WriteJSON(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": struct{}{}})
+			if len(n.subs) == 2 {
+				close(n.ready)
+			}
+			n.mu.Unlock()
+		}
+	}))
+	t.Cleanup(n.srv.Close)
+	return n
+}
+
+func (n *fakeNode) remote() string {
+	return "tcp://" + strings.TrimPrefix(n.srv.URL, "http://")
+}
+
+func (n *fakeNode) send(t *testing.T, query, eventType, value string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	result := fmt.Sprintf(`{"query":%q,"data":{"type":%q,"value":%s},"events":{}}`, query, eventType, value)
+	err := n.conn.WriteJSON(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      n.subs[query],
+		"result":  json.RawMessage(result),
+	})
+	require.NoError(t, err)
+}
+
+func (n *fakeNode) newBlock(t *testing.T, height int64) {
+	n.send(t, queryNewBlock, "tendermint/event/NewBlock", fmt.Sprintf(`{"block":{"header":{"height":"%d"}}}`, height))
+}
+
+func (n *fakeNode) tx(t *testing.T, height int64) {
+	n.send(t, queryTx, "tendermint/event/Tx", fmt.Sprintf(`{"TxResult":{"height":"%d","index":0,"tx":null,"result":{}}}`, height))
+}
+
+func receive(t *testing.T, s *Subscription) int64 {
+	select {
+	case h, ok := <-s.Heights():
+		require.True(t, ok, "subscription ended: %v", s.Err())
+		return h
+	case <-time.After(5 * time.Second):
+		t.Fatal("no height received")
+		return 0
+	}
+}
+
+func TestSubscriptionHeights(t *testing.T) {
+	n := newFakeNode(t)
+
+	s, err := Subscribe(context.Background(), n.remote(), 5*time.Second)
+	require.NoError(t, err)
+	defer s.Close()
+	<-n.ready
+
+	n.newBlock(t, 10)
+	require.Equal(t, int64(10), receive(t, s))
+
+	n.tx(t, 11)
+	require.Equal(t, int64(11), receive(t, s))
+
+	// heights which are not newer than the last one are not delivered again
+	n.newBlock(t, 11)
+	n.newBlock(t, 12)
+	require.Equal(t, int64(12), receive(t, s))
+}
+
+func TestSubscriptionIdle(t *testing.T) {
+	n := newFakeNode(t)
+
+	s, err := Subscribe(context.Background(), n.remote(), 200*time.Millisecond)
+	require.NoError(t, err)
+	defer s.Close()
+
+	select {
+	case _, ok := <-s.Heights():
+		require.False(t, ok)
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscription did not end")
+	}
+	require.ErrorIs(t, s.Err(), ErrIdle)
+}
+
+func TestSubscriptionClosedEvents(t *testing.T) {
+	s := &Subscription{
+		heights: make(chan int64),
+		quit:    make(chan struct{}),
+	}
+	blocks, txs := make(chan ctypes.ResultEvent), make(chan ctypes.ResultEvent)
+	go s.run(context.Background(), blocks, txs, 5*time.Second)
+	close(blocks)
+
+	select {
+	case _, ok := <-s.Heights():
+		require.False(t, ok)
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscription did not end")
+	}
+	require.ErrorIs(t, s.Err(), ErrClosed)
+}